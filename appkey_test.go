@@ -0,0 +1,114 @@
+package gvite_demo
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/hex"
+	"hash"
+	"testing"
+
+	"github.com/vitelabs/go-vite/crypto"
+	"github.com/vitelabs/go-vite/wallet/entropystore"
+	"github.com/vitelabs/go-vite/wallet/testkit"
+	"github.com/vitelabs/go-vite/wallet/walleterrors"
+	"golang.org/x/crypto/blake2b"
+)
+
+// go test -run TestCrypto_HKDF -v
+func TestCrypto_HKDF(t *testing.T) {
+	newHash := func() hash.Hash {
+		h, _ := blake2b.New512(nil)
+		return h
+	}
+	// the hand made HMAC must agree with crypto/hmac, for short and hashed long keys
+	for _, key := range [][]byte{nil, []byte("key"), bytes.Repeat([]byte{7}, 200)} {
+		mac := hmac.New(newHash, key)
+		mac.Write([]byte("message"))
+		if got := crypto.HMAC(key, []byte("mes"), []byte("sage")); !bytes.Equal(got, mac.Sum(nil)) {
+			t.Fatalf("hmac of key %x is %x", key, got)
+		}
+	}
+
+	// RFC 5869 with crypto/hmac as the reference
+	secret, salt, info := []byte("input key material"), []byte("salt"), []byte("info")
+	mac := hmac.New(newHash, salt)
+	mac.Write(secret)
+	prk := mac.Sum(nil)
+	var want, block []byte
+	for i := byte(1); len(want) < 100; i++ {
+		mac = hmac.New(newHash, prk)
+		mac.Write(block)
+		mac.Write(info)
+		mac.Write([]byte{i})
+		block = mac.Sum(nil)
+		want = append(want, block...)
+	}
+	got, err := crypto.HKDF(secret, salt, info, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want[:100]) {
+		t.Fatalf("hkdf got %x", got)
+	}
+	if _, err := crypto.HKDF(secret, salt, info, 255*64+1); err != crypto.ErrHKDFLength {
+		t.Fatalf("expect ErrHKDFLength got %v", err)
+	}
+}
+
+// the "db" key of testkit.Mnemonic24
+const appKeyVector = "3be7502c747070bb8f572b9c25c07e5192262cbfad13da076ab113ac79cdf571"
+
+// go test -run TestEntropyStore_AppKey -v
+func TestEntropyStore_AppKey(t *testing.T) {
+	manager := testkit.NewWallet("appkey")
+	em, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := em.AppKey("db"); err != walleterrors.ErrLocked {
+		t.Fatalf("expect ErrLocked got %v", err)
+	}
+	key, err := em.AppKeyWithPassphrase(testkit.Passphrase, "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != entropystore.AppKeySize {
+		t.Fatalf("unexpected key size %v", len(key))
+	}
+	if err := em.Unlock(testkit.Passphrase); err != nil {
+		t.Fatal(err)
+	}
+	again, err := em.AppKey("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(key, again) {
+		t.Fatal("the key of a purpose changed")
+	}
+	other, err := em.AppKey("db2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(key, other) {
+		t.Fatal("two purposes share a key")
+	}
+	if _, err := em.AppKey(""); err != entropystore.ErrEmptyPurpose {
+		t.Fatalf("expect ErrEmptyPurpose got %v", err)
+	}
+
+	// no application key is the private key of an account
+	for i := uint32(0); i < 3; i++ {
+		_, account, err := em.DeriveForIndexPath(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(account.Key, key) || bytes.Equal(account.Key, other) {
+			t.Fatalf("the key of account %v is an application key", i)
+		}
+	}
+
+	// pin the derivation, a change would lose the data the apps encrypted
+	if hex.EncodeToString(key) != appKeyVector {
+		t.Fatalf("the db key of the test mnemonic is %x", key)
+	}
+}
@@ -0,0 +1,155 @@
+package gvite_demo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/storage"
+	"github.com/vitelabs/go-vite/wallet/testkit"
+)
+
+// go test -run TestWallet_Backup -v
+func TestWallet_Backup(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := wallet.New(&wallet.Config{
+		DataDir:              tmpDir,
+		MaxSearchIndex:       500,
+		SkipPassphrasePolicy: true,
+	})
+	manager.Start()
+	_, first, err := manager.NewMnemonicAndEntropyStore("123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, second, err := manager.NewMnemonicAndEntropyStore("654321")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetStoreName(first.GetEntropyStoreFile(), "hot"); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := manager.ExportBackup("backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := wallet.New(&wallet.Config{Storage: storage.NewMemoryStorage(), SkipPassphrasePolicy: true})
+	restored.Start()
+	if _, err := restored.InspectBackup(archive, "wrong"); err == nil {
+		t.Fatal("expect wrong backup passphrase to fail")
+	}
+
+	result, err := restored.RestoreBackup(archive, "backup", wallet.RestoreOptions{Only: []types.Address{first.GetPrimaryAddr()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Restored) != 1 || result.Restored[0] != first.GetPrimaryAddr() {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	entries, err := restored.InspectBackup(archive, "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.Conflict != (entry.PrimaryAddr == first.GetPrimaryAddr()) {
+			t.Fatalf("unexpected entry %+v", entry)
+		}
+	}
+
+	result, err = restored.RestoreBackup(archive, "backup", wallet.RestoreOptions{RestoreConfig: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Restored) != 1 || len(result.Conflicts) != 1 || result.Conflicts[0] != first.GetPrimaryAddr() {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	hot, err := restored.GetEntropyStoreManagerByName("hot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the restored config reaches the store loaded before it
+	if hot.MaxSearchIndex() != 500 {
+		t.Fatalf("expect the restored window got %v", hot.MaxSearchIndex())
+	}
+	if err := hot.Unlock("123456"); err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.Unlock(second.GetPrimaryAddr().Hex(), "654321"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// go test -run TestWallet_BackupOverwrite -v
+func TestWallet_BackupOverwrite(t *testing.T) {
+	config := testkit.Config("backup overwrite")
+	config.SkipPassphrasePolicy = false
+	source := wallet.New(config)
+	source.Start()
+	em, err := source.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the backup passphrase follows the passphrase policy too
+	if _, err := source.ExportBackup("backup"); err == nil {
+		t.Fatal("expect a weak backup passphrase to fail")
+	}
+	archive, err := source.ExportBackup(testkit.BackupPassphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := wallet.New(&wallet.Config{DataDir: otherDir, SkipPassphrasePolicy: true})
+	other.Start()
+	outside, err := other.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := wallet.New(&wallet.Config{DataDir: dataDir, SkipPassphrasePolicy: true})
+	manager.Start()
+	if err := manager.AddEntropyStore(outside.GetEntropyStoreFile()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the store outside DataDir is replaced, not left behind
+	result, err := manager.RestoreBackup(archive, testkit.BackupPassphrase, wallet.RestoreOptions{Overwrite: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Restored) != 1 || result.Restored[0] != em.GetPrimaryAddr() {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if _, err := os.Stat(outside.GetEntropyStoreFile()); !os.IsNotExist(err) {
+		t.Fatalf("expect the replaced store file to be gone got %v", err)
+	}
+	trash, err := manager.ListTrash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trash) != 1 || trash[0].DeletedFrom != outside.GetEntropyStoreFile() {
+		t.Fatalf("unexpected trash %+v", trash)
+	}
+	if files := manager.ListAllEntropyFiles(); len(files) != 1 || filepath.Dir(files[0]) != dataDir {
+		t.Fatalf("unexpected stores %v", files)
+	}
+	if err := manager.Unlock(em.GetPrimaryAddr().Hex(), testkit.Passphrase); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,101 @@
+package gvite_demo
+
+import (
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/walleterrors"
+)
+
+// go test -run TestWallet_BackupQuiz -v
+func TestWallet_BackupQuiz(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := wallet.New(&wallet.Config{
+		DataDir:              tmpDir,
+		SkipPassphrasePolicy: true,
+	})
+	manager.Start()
+	mnemonic, storeManager, err := manager.NewMnemonicAndEntropyStore("123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := storeManager.GetEntropyStoreFile()
+	if unverified := manager.BackupUnverifiedStores(); len(unverified) != 1 || unverified[0] != store {
+		t.Fatalf("expect the new store to be unverified got %v", unverified)
+	}
+
+	quiz, err := manager.NewBackupQuiz(store, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quiz.Positions) != wallet.DefaultBackupQuizWords {
+		t.Fatalf("unexpected quiz %+v", quiz)
+	}
+	words := strings.Fields(mnemonic)
+	answers := make([]string, len(quiz.Positions))
+	for i, p := range quiz.Positions {
+		if i > 0 && p <= quiz.Positions[i-1] || p < 1 || p > len(words) {
+			t.Fatalf("unexpected positions %v", quiz.Positions)
+		}
+		answers[i] = words[p-1]
+	}
+
+	// one right word, or the quiz cut down to it, does not verify
+	one := &wallet.BackupQuiz{EntropyStore: quiz.EntropyStore, Positions: quiz.Positions[:1]}
+	if err := manager.VerifyBackupQuiz(one, "123456", answers[:1]); err != walleterrors.ErrBackupMismatch {
+		t.Fatalf("expect ErrBackupMismatch got %v", err)
+	}
+	if err := manager.VerifyBackupQuiz(quiz, "123456", append(answers[:1:1], "", "", "")); err != walleterrors.ErrBackupMismatch {
+		t.Fatalf("expect ErrBackupMismatch got %v", err)
+	}
+	// positions the store did not issue do not verify, even with their right words
+	changed := &wallet.BackupQuiz{EntropyStore: quiz.EntropyStore, Positions: append([]int(nil), quiz.Positions...)}
+	changedAnswers := append([]string(nil), answers...)
+	for p := 1; p <= len(words); p++ {
+		if sort.SearchInts(quiz.Positions, p) == len(quiz.Positions) || quiz.Positions[sort.SearchInts(quiz.Positions, p)] != p {
+			changed.Positions[0], changedAnswers[0] = p, words[p-1]
+			break
+		}
+	}
+	if err := manager.VerifyBackupQuiz(changed, "123456", changedAnswers); err != walleterrors.ErrBackupMismatch {
+		t.Fatalf("expect a changed quiz to fail got %v", err)
+	}
+	if quiz, err = manager.NewBackupQuiz(store, 1); err != nil || len(quiz.Positions) != wallet.DefaultBackupQuizWords {
+		t.Fatalf("unexpected quiz %+v %v", quiz, err)
+	}
+	for i, p := range quiz.Positions {
+		answers[i] = words[p-1]
+	}
+	if unverified := manager.BackupUnverifiedStores(); len(unverified) != 1 {
+		t.Fatalf("expect the store to stay unverified got %v", unverified)
+	}
+
+	wrong := append([]string(nil), answers...)
+	wrong[1] = "zoo"
+	if err := manager.VerifyBackupQuiz(quiz, "123456", wrong); err != walleterrors.ErrBackupMismatch {
+		t.Fatalf("expect ErrBackupMismatch got %v", err)
+	}
+	if err := manager.Unlock(store, "123456"); err != nil {
+		t.Fatal(err)
+	}
+	answers[0] = " " + strings.ToUpper(answers[0])
+	if err := manager.VerifyBackupQuiz(quiz, "", answers); err != nil {
+		t.Fatal(err)
+	}
+	if unverified := manager.BackupUnverifiedStores(); len(unverified) != 0 {
+		t.Fatalf("expect no unverified store got %v", unverified)
+	}
+
+	if err := manager.VerifyBackupPhrase(store, "", strings.Join(words[1:], " ")); err != walleterrors.ErrBackupMismatch {
+		t.Fatalf("expect ErrBackupMismatch got %v", err)
+	}
+	if err := manager.VerifyBackupPhrase(store, "", mnemonic); err != nil {
+		t.Fatal(err)
+	}
+}
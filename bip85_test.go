@@ -0,0 +1,131 @@
+package gvite_demo
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/bip85"
+	"github.com/vitelabs/go-vite/wallet/entropystore"
+	"github.com/vitelabs/go-vite/wallet/testkit"
+	"github.com/vitelabs/go-vite/wallet/walleterrors"
+)
+
+// the test vectors of BIP85
+const bip85Xprv = "xprv9s21ZrQH143K2LBWUUQRFXhucrQqBpKdRRxNVq2zBqsx8HVqFk2uYo8kmbaLLHRdqtQpUm98uKfu3vca1LqdGhUtyoFnCNkfmXRyPXLjbKb"
+
+// go test -run TestBIP85_Vectors -v
+func TestBIP85_Vectors(t *testing.T) {
+	d, err := bip85.XprvDeriver(bip85Xprv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		path, key, entropy string
+	}{
+		{"m/83696968'/0'/0'", "cca20ccb0e9a90feb0912870c3323b24874b0ca3d8018c4b96d0b97c0e82ded0",
+			"efecfbccffea313214232d29e71563d941229afb4338c21f9517c41aaa0d16f00b83d2a09ef747e7a64e8e2bd5a14869e693da66ce94ac2da570ab7ee48618f7"},
+		{"m/83696968'/0'/1'", "503776919131758bb7de7beb6c0ae24894f4ec042c26032890c29359216e21ba",
+			"70c6e3e8ebee8dc4c0dbba66076819bb8c09672527c4277ca8729532ad711872218f826919f6b67218adde99018a6df9095ab2b58d803b5b93ec9802085a690e"},
+	} {
+		key, err := d(v.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(key) != v.key {
+			t.Fatalf("%v derived key %x", v.path, key)
+		}
+		entropy, err := bip85.Entropy(d, v.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(entropy) != v.entropy {
+			t.Fatalf("%v entropy %x", v.path, entropy)
+		}
+	}
+
+	for words, want := range map[int]string{
+		12: "girl mad pet galaxy egg matter matrix prison refuse sense ordinary nose",
+		18: "near account window bike charge season chef number sketch tomorrow excuse sniff circle vital hockey outdoor supply token",
+		24: "puppy ocean match cereal symbol another shed magic wrap hammer bulb intact gadget divorce twin tonight reason outdoor destroy simple truth cigar social volcano",
+	} {
+		mnemonic, err := bip85.Mnemonic(d, words, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mnemonic != want {
+			t.Fatalf("%v words got %v", words, mnemonic)
+		}
+	}
+
+	h, err := bip85.Hex(d, 64, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h != "492db4698cf3b73a5a24998aa3e9d7fa96275d85724a91e71aa2d645442f878555d078fd1f1f67e368976f04137b1f7a0d19232136ca50c44614af72b5582a5c" {
+		t.Fatalf("hex got %v", h)
+	}
+	if _, err := bip85.Mnemonic(d, 15, 0); err != bip85.ErrInvalidWordCount {
+		t.Fatalf("expect ErrInvalidWordCount got %v", err)
+	}
+}
+
+// go test -run TestWallet_ChildEntropyStore -v
+func TestWallet_ChildEntropyStore(t *testing.T) {
+	manager := testkit.NewWallet("bip85")
+	parent, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := manager.NewChildEntropyStore(parent.GetEntropyStoreFile(), 24, 0, testkit.Passphrase); err != walleterrors.ErrLocked {
+		t.Fatalf("expect ErrLocked got %v", err)
+	}
+	if err := parent.Unlock(testkit.Passphrase); err != nil {
+		t.Fatal(err)
+	}
+
+	mnemonic, child, err := manager.NewChildEntropyStore(parent.GetEntropyStoreFile(), 24, 0, "child")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := parent.ChildMnemonic(24, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != mnemonic {
+		t.Fatalf("the child changed from %v to %v", mnemonic, again)
+	}
+	if other, _ := parent.ChildMnemonic(24, 1); other == mnemonic {
+		t.Fatal("index 1 gave the child of index 0")
+	}
+	if short, _ := parent.ChildMnemonic(12, 0); short == "" || short == mnemonic[:len(short)] {
+		t.Fatalf("the 12 words child must not be a prefix of the 24 words one, got %v", short)
+	}
+	if child.GetPrimaryAddr() == parent.GetPrimaryAddr() {
+		t.Fatal("the child has the address of its parent")
+	}
+	if err := child.Unlock("child"); err != nil {
+		t.Fatal(err)
+	}
+	md, err := child.Metadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md.Source != entropystore.SourceBIP85 || md.BackupUnverified {
+		t.Fatalf("unexpected metadata %+v", md)
+	}
+
+	// another wallet with the same parent mnemonic derives the same child
+	other := wallet.New(testkit.Config("another"))
+	other.Start()
+	sameParent, err := other.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sameParent.Unlock(testkit.Passphrase); err != nil {
+		t.Fatal(err)
+	}
+	if m, _ := sameParent.ChildMnemonic(24, 0); m != mnemonic {
+		t.Fatalf("expect %v got %v", mnemonic, m)
+	}
+}
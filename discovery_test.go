@@ -0,0 +1,147 @@
+package gvite_demo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/discovery"
+	"github.com/vitelabs/go-vite/wallet/testkit"
+	"github.com/vitelabs/go-vite/wallet/walleterrors"
+)
+
+// go test -run TestWallet_Discover -v
+func TestWallet_Discover(t *testing.T) {
+	config := testkit.Config("discover")
+	config.MaxSearchIndex = 10
+	manager := wallet.New(config)
+	manager.Start()
+	recovered, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a store loaded at start gets the window of the config
+	manager = wallet.New(config)
+	manager.Start()
+	em, err := manager.GetEntropyStoreManager(recovered.GetEntropyStoreFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := em.Unlock(testkit.Passphrase); err != nil {
+		t.Fatal(err)
+	}
+	list, err := em.ListAddress(0, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs := make([]types.Address, len(list))
+	for i, info := range list {
+		addrs[i] = info.Address
+	}
+
+	// 0, 3 and 12 are used, 12 is beyond the window of the wallet but within the gap of 3
+	ledger := discovery.NewLedger(addrs[0], addrs[3], addrs[12], addrs[39])
+	if _, _, _, err := manager.GlobalFindAddr(addrs[12]); err != walleterrors.ErrAddressNotFound {
+		t.Fatalf("expect ErrAddressNotFound got %v", err)
+	}
+	result, err := manager.Discover(em.GetEntropyStoreFile(), ledger, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Used) != 3 || result.HighestUsed != 12 || result.Scanned != 23 || result.SearchLimit != 23 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if _, _, index, err := manager.GlobalFindAddr(addrs[12]); err != nil || index != 12 {
+		t.Fatalf("expect index 12 got %v %v", index, err)
+	}
+
+	// the window is kept, a restarted wallet uses it
+	reopened := wallet.New(config)
+	reopened.Start()
+	sm, err := reopened.GetEntropyStoreManager(em.GetEntropyStoreFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sm.MaxSearchIndex() != 23 {
+		t.Fatalf("expect the discovered window got %v", sm.MaxSearchIndex())
+	}
+	md, err := sm.Metadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md.HighestUsedIndex == nil || *md.HighestUsedIndex != 12 {
+		t.Fatalf("unexpected metadata %+v", md)
+	}
+
+	if _, err := sm.Discover(ledger, 0); err != walleterrors.ErrLocked {
+		t.Fatalf("expect ErrLocked got %v", err)
+	}
+
+	// a hidden store applies the window but keeps nothing that tells about the seed unlocked
+	_, _, hidden, err := manager.NewHiddenEntropyStore(testkit.Passphrase, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := hidden.Unlock(testkit.Passphrase); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hidden.Discover(ledger, 10); err != nil {
+		t.Fatal(err)
+	}
+	if hidden.MaxSearchIndex() != 10 {
+		t.Fatalf("expect the discovered window got %v", hidden.MaxSearchIndex())
+	}
+	if md, err := hidden.Metadata(); err != nil || md.SearchLimit != 0 || md.HighestUsedIndex != nil {
+		t.Fatalf("unexpected metadata %+v %v", md, err)
+	}
+}
+
+// go test -run TestDiscovery_Ledger -v
+func TestDiscovery_Ledger(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "ledger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	path := filepath.Join(tmpDir, "ledger.json")
+
+	empty, err := discovery.LoadLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	derive := func(index uint32) (types.Address, error) {
+		var addr types.Address
+		addr[0] = byte(index)
+		return addr, nil
+	}
+	result, err := discovery.Scan(derive, empty, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Used) != 0 || result.Scanned != discovery.DefaultGapLimit || result.SearchLimit != discovery.DefaultGapLimit {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	a, _ := derive(2)
+	empty.MarkUsed(a)
+	if err := empty.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := discovery.LoadLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if used, _ := loaded.IsUsed(a); !used {
+		t.Fatal("the saved address is not used")
+	}
+	result, err = discovery.Scan(derive, loaded, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.HighestUsed != 2 || result.SearchLimit != 8 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
@@ -0,0 +1,176 @@
+package gvite_demo
+
+import (
+	"context"
+	"io/ioutil"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/testkit"
+	"github.com/vitelabs/go-vite/wallet/walleterrors"
+)
+
+// go test -run TestWallet_Subscribe -v
+func TestWallet_Subscribe(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := wallet.New(&wallet.Config{
+		DataDir:              tmpDir,
+		SkipPassphrasePolicy: true,
+	})
+	manager.Start()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	all := manager.Subscribe(ctx, wallet.EventFilter{}, 0)
+	small := manager.Subscribe(context.Background(), wallet.EventFilter{Types: []wallet.EventType{wallet.StoreAdded, wallet.Unlocked}}, 1)
+	defer small.Unsubscribe()
+
+	_, storeManager, err := manager.NewMnemonicAndEntropyStore("123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := manager.Subscribe(context.Background(), wallet.EventFilter{EntropyStore: types.AddressRegister.Hex()}, 0)
+	defer other.Unsubscribe()
+
+	if err := manager.Unlock(storeManager.GetEntropyStoreFile(), "wrong"); err == nil {
+		t.Fatal("expect unlock failure")
+	}
+	if err := manager.Unlock(storeManager.GetEntropyStoreFile(), "123456"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := storeManager.SignData(storeManager.GetPrimaryAddr(), []byte("vite")); err != nil {
+		t.Fatal(err)
+	}
+	manager.Lock(storeManager.GetEntropyStoreFile())
+
+	expect := []wallet.EventType{wallet.StoreAdded, wallet.UnlockFailed, wallet.Unlocked, wallet.Signed, wallet.Locked}
+	for _, typ := range expect {
+		ev := <-all.C
+		if ev.Type != typ {
+			t.Fatalf("expect %v got %v", typ, ev.Type)
+		}
+		if ev.PrimaryAddr != storeManager.GetPrimaryAddr() {
+			t.Fatal("primary address mismatch")
+		}
+	}
+
+	if ev := <-small.C; ev.Type != wallet.StoreAdded {
+		t.Fatalf("expect StoreAdded got %v", ev.Type)
+	}
+	if small.Dropped() != 1 {
+		t.Fatalf("expect 1 dropped event got %v", small.Dropped())
+	}
+	if len(other.C) != 0 {
+		t.Fatal("filtered subscription received events")
+	}
+
+	cancel()
+	for range all.C {
+	}
+}
+
+// go test -race -run TestWallet_AutoLock -v
+func TestWallet_AutoLock(t *testing.T) {
+	manager := testkit.NewWallet("autolock")
+	em, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := manager.Subscribe(context.Background(), wallet.EventFilter{Types: []wallet.EventType{wallet.AutoLocked}}, 4)
+	defer events.Unsubscribe()
+
+	if err := manager.UnlockFor(em.GetEntropyStoreFile(), testkit.Passphrase, 5*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	// the store is used while the timer locks it
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for em.IsUnlocked() {
+				if _, _, err := em.FindAddr(em.GetPrimaryAddr()); err != nil && err != walleterrors.ErrLocked {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	select {
+	case ev := <-events.C:
+		if ev.PrimaryAddr != em.GetPrimaryAddr() {
+			t.Fatalf("unexpected event %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expect an AutoLocked event")
+	}
+
+	// a timer of an earlier unlock does not lock the store again
+	if err := manager.UnlockFor(em.GetEntropyStoreFile(), testkit.Passphrase, 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Lock(em.GetEntropyStoreFile()); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Unlock(em.GetEntropyStoreFile(), testkit.Passphrase); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if !em.IsUnlocked() || len(events.C) != 0 {
+		t.Fatal("expect the store still unlocked")
+	}
+}
+
+// go test -run TestWallet_UnsubscribeEndsGoroutine -v
+func TestWallet_UnsubscribeEndsGoroutine(t *testing.T) {
+	manager := testkit.NewWallet("unsubscribe")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	before := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		manager.Subscribe(ctx, wallet.EventFilter{}, 0).Unsubscribe()
+	}
+	for i := 0; i < 100 && runtime.NumGoroutine() > before; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Fatalf("expect %v goroutines got %v", before, n)
+	}
+}
+
+// go test -race -run TestWallet_ConcurrentStores -v
+func TestWallet_ConcurrentStores(t *testing.T) {
+	manager := testkit.NewWallet("concurrent stores")
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, em, err := manager.NewMnemonicAndEntropyStore(testkit.Passphrase)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := manager.ResolveStore(em.GetPrimaryAddr().String()); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			manager.ListAllEntropyFiles()
+			manager.RefreshCache()
+			manager.GlobalCheckAddrUnlock(types.Address{})
+		}()
+	}
+	wg.Wait()
+	if files := manager.ListAllEntropyFiles(); len(files) != 4 {
+		t.Fatalf("expect 4 stores got %v", files)
+	}
+}
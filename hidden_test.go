@@ -0,0 +1,115 @@
+package gvite_demo
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/entropystore"
+	"github.com/vitelabs/go-vite/wallet/walleterrors"
+)
+
+// go test -run TestWallet_HiddenStore -v
+func TestWallet_HiddenStore(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := wallet.New(&wallet.Config{
+		DataDir:              tmpDir,
+		SkipPassphrasePolicy: true,
+	})
+	manager.Start()
+	mnemonic, decoyMnemonic, storeManager, err := manager.NewHiddenEntropyStore("123456", "duress")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, plainManager, err := manager.NewHiddenEntropyStore("123456", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := storeManager.GetEntropyStoreFile()
+	if !strings.HasPrefix(filepath.Base(store), entropystore.HiddenStorePrefix) {
+		t.Fatalf("unexpected file name %v", store)
+	}
+
+	// with or without a decoy the files only differ in their random bytes
+	withDecoy, err := ioutil.ReadFile(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withoutDecoy, err := ioutil.ReadFile(plainManager.GetEntropyStoreFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	randomBytes := regexp.MustCompile(`"[0-9a-f]{8,}"|"timestamp":[0-9]+`)
+	if randomBytes.ReplaceAllString(string(withDecoy), "x") != randomBytes.ReplaceAllString(string(withoutDecoy), "x") {
+		t.Fatalf("the files differ\n%s\n%s", withDecoy, withoutDecoy)
+	}
+	if strings.Contains(string(withDecoy), "vite_") {
+		t.Fatalf("the file reveals an address %s", withDecoy)
+	}
+
+	realAddr, err := entropystore.MnemonicToPrimaryAddr(mnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoyAddr, err := entropystore.MnemonicToPrimaryAddr(decoyMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub := manager.Subscribe(context.Background(), wallet.EventFilter{EntropyStore: store}, 0)
+	defer sub.Unsubscribe()
+	for _, c := range []struct {
+		passphrase string
+		addr       string
+	}{{"duress", decoyAddr.String()}, {"123456", realAddr.String()}} {
+		if err := manager.Unlock(store, c.passphrase); err != nil {
+			t.Fatal(err)
+		}
+		if ev := <-sub.C; ev.Type != wallet.Unlocked || ev.PrimaryAddr.String() != c.addr {
+			t.Fatalf("unexpected event %+v", ev)
+		}
+		manager.Lock(store)
+		<-sub.C
+	}
+
+	restarted := wallet.New(&wallet.Config{
+		DataDir:              tmpDir,
+		SkipPassphrasePolicy: true,
+	})
+	restarted.Start()
+	// the decoy passphrase can neither replace the real seed nor become the passphrase of the store
+	for _, oldDecoy := range []string{"", "duress"} {
+		if _, err := restarted.SetDecoy(store, "duress", oldDecoy, "another"); err != walleterrors.ErrNotDecoy {
+			t.Fatalf("expect ErrNotDecoy got %v", err)
+		}
+	}
+	if err := restarted.ChangePassphrase(store, "123456", "", "duress", ""); err != walleterrors.ErrDecoyPassphrase {
+		t.Fatalf("expect ErrDecoyPassphrase got %v", err)
+	}
+	if _, err := restarted.SetDecoy(store, "123456", "duress", "123456"); err != walleterrors.ErrDecoyPassphrase {
+		t.Fatalf("expect ErrDecoyPassphrase got %v", err)
+	}
+	if _, _, _, err := restarted.NewHiddenEntropyStore("x", "x"); err != walleterrors.ErrDecoyPassphrase {
+		t.Fatalf("expect ErrDecoyPassphrase got %v", err)
+	}
+
+	if _, err := restarted.SetDecoy(store, "123456", "duress", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := restarted.Unlock(store, "duress"); err != walleterrors.ErrDecryptEntropy {
+		t.Fatalf("expect ErrDecryptEntropy got %v", err)
+	}
+	// without a decoy only the passphrase of the store is needed
+	if _, err := restarted.SetDecoy(store, "123456", "", "duress"); err != nil {
+		t.Fatal(err)
+	}
+	if err := restarted.Unlock(store, "123456"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,65 @@
+package gvite_demo
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/walleterrors"
+)
+
+// go test -run TestWallet_Keyfile -v
+func TestWallet_Keyfile(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyfile := filepath.Join(tmpDir, "keyfile.bin")
+	otherKeyfile := filepath.Join(tmpDir, "other.bin")
+	if err := ioutil.WriteFile(keyfile, []byte("keyfile on removable media"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(otherKeyfile, []byte("another keyfile"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	manager := wallet.New(&wallet.Config{
+		DataDir:              tmpDir,
+		SkipPassphrasePolicy: true,
+	})
+	manager.Start()
+	_, storeManager, err := manager.NewMnemonicAndEntropyStoreWithKeyfile("123456", keyfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := storeManager.GetEntropyStoreFile()
+
+	content, err := ioutil.ReadFile(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "keyfile.bin") || !strings.Contains(string(content), `"keyfile":true`) {
+		t.Fatalf("unexpected store %s", content)
+	}
+	if required, err := storeManager.KeyfileRequired(); err != nil || !required {
+		t.Fatalf("expect a required keyfile got %v %v", required, err)
+	}
+
+	if err := manager.Unlock(store, "123456"); err != walleterrors.ErrKeyfileRequired {
+		t.Fatalf("expect ErrKeyfileRequired got %v", err)
+	}
+	if err := manager.UnlockWithKeyfile(store, "123456", otherKeyfile, 0); err != walleterrors.ErrDecryptEntropy {
+		t.Fatalf("expect ErrDecryptEntropy got %v", err)
+	}
+	if err := manager.UnlockWithKeyfile(store, "123456", keyfile, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.ChangePassphrase(store, "123456", keyfile, "654321", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Unlock(store, "654321"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,75 @@
+package gvite_demo
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/entropystore"
+	"github.com/vitelabs/go-vite/wallet/walleterrors"
+)
+
+// go test -run TestWallet_KeySlots -v
+func TestWallet_KeySlots(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := wallet.New(&wallet.Config{
+		DataDir:              tmpDir,
+		SkipPassphrasePolicy: true,
+	})
+	manager.Start()
+	_, storeManager, err := manager.NewMnemonicAndEntropyStore("123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := storeManager.GetEntropyStoreFile()
+
+	recoveryKey, recoveryId, err := manager.AddRecoveryKeySlot(store, "123456", "paper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyfile := filepath.Join(tmpDir, "keyfile")
+	if err := ioutil.WriteFile(keyfile, []byte("some random bytes"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.AddKeyfileSlot(store, strings.ToUpper(recoveryKey), keyfile, "usb"); err != nil {
+		t.Fatal(err)
+	}
+
+	slots, err := manager.ListKeySlots(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slots) != 3 || slots[0].Type != entropystore.KeySlotPassphrase || slots[1].Id != recoveryId || slots[2].Label != "usb" {
+		t.Fatalf("unexpected slots %+v", slots)
+	}
+
+	if err := manager.UnlockWithKeyfile(store, "", keyfile, 0); err != nil {
+		t.Fatal(err)
+	}
+	manager.Lock(store)
+
+	if err := manager.RevokeKeySlot(store, recoveryKey, slots[0].Id); err != nil {
+		t.Fatal(err)
+	}
+	// only the recovery key and the keyfile are left
+	if err := manager.Unlock(store, "123456"); err != walleterrors.ErrKeyfileRequired {
+		t.Fatalf("expect ErrKeyfileRequired got %v", err)
+	}
+	if err := manager.Unlock(store, recoveryKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.RevokeKeySlot(store, recoveryKey, slots[0].Id); err != walleterrors.ErrKeySlotNotFound {
+		t.Fatalf("expect ErrKeySlotNotFound got %v", err)
+	}
+	if err := manager.RevokeKeySlot(store, recoveryKey, slots[2].Id); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.RevokeKeySlot(store, recoveryKey, recoveryId); err != walleterrors.ErrLastKeySlot {
+		t.Fatalf("expect ErrLastKeySlot got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package gvite_demo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/entropystore"
+	"github.com/vitelabs/go-vite/wallet/testkit"
+	"github.com/vitelabs/go-vite/wallet/walleterrors"
+)
+
+// go test -run TestWallet_Metadata -v
+func TestWallet_Metadata(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := wallet.New(&wallet.Config{
+		DataDir:              tmpDir,
+		SkipPassphrasePolicy: true,
+	})
+	manager.Start()
+	_, storeManager, err := manager.NewMnemonicAndEntropyStore("123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := storeManager.GetEntropyStoreFile()
+
+	if err := manager.SetStoreName(store, "treasury"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetAddressLabel(store, 1, "deposit"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetAddressHidden(store, 2, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// the sidecar file must not be picked up as a store
+	manager.Start()
+	if files := manager.ListAllEntropyFiles(); len(files) != 1 {
+		t.Fatalf("expect 1 store got %v", files)
+	}
+	named, err := manager.GetEntropyStoreManagerByName("treasury")
+	if err != nil {
+		t.Fatal(err)
+	}
+	md, err := manager.GetStoreMetadata(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md.Source != entropystore.SourceNew || md.CreatedAt == 0 {
+		t.Fatalf("unexpected metadata %+v", md)
+	}
+
+	if err := named.Unlock("123456"); err != nil {
+		t.Fatal(err)
+	}
+	list, err := named.ListAddress(0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list[0].Label != "" || list[1].Label != "deposit" || !list[2].Hidden || list[2].Index != 2 {
+		t.Fatalf("unexpected address list %+v", list)
+	}
+
+	_, other, err := manager.NewMnemonicAndEntropyStore("123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetStoreName(other.GetEntropyStoreFile(), "treasury"); err != walleterrors.ErrStoreNameExists {
+		t.Fatalf("expect ErrStoreNameExists got %v", err)
+	}
+}
+
+// go test -run TestWallet_ConcurrentMetadata -v
+func TestWallet_ConcurrentMetadata(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a file storage, every read and write yields so the updates interleave
+	config := testkit.Config("metadata")
+	config.Storage = nil
+	config.DataDir = tmpDir
+	manager := wallet.New(config)
+	manager.Start()
+	em, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := em.GetEntropyStoreFile()
+	var wg sync.WaitGroup
+	for i := uint32(0); i < 20; i++ {
+		wg.Add(1)
+		go func(index uint32) {
+			defer wg.Done()
+			if err := manager.SetAddressLabel(store, index, fmt.Sprint(index)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	md, err := em.Metadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := uint32(0); i < 20; i++ {
+		if md.Address(i).Label != fmt.Sprint(i) {
+			t.Fatalf("the label of %v is lost %+v", i, md.Addresses)
+		}
+	}
+}
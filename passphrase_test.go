@@ -0,0 +1,70 @@
+package gvite_demo
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/passphrase"
+)
+
+// go test -run TestPassphrase_Policy -v
+func TestPassphrase_Policy(t *testing.T) {
+	policy := passphrase.DefaultPolicy
+	policy.Blocklist = []string{"Correct-Battery-Staple"}
+	mnemonic := "abandon ability able about above absent absorb abstract absurd abuse access accident"
+
+	for _, c := range []struct {
+		passphrase string
+		rules      []passphrase.Rule
+	}{
+		{"", []passphrase.Rule{passphrase.RuleMinLength, passphrase.RuleEntropy}},
+		{"123456", []passphrase.Rule{passphrase.RuleMinLength, passphrase.RuleEntropy, passphrase.RuleBlocklist}},
+		{"abcdefghijkl", []passphrase.Rule{passphrase.RuleEntropy}},
+		{"PASSWORD", []passphrase.Rule{passphrase.RuleEntropy, passphrase.RuleBlocklist}},
+		{"correct-battery-staple", []passphrase.Rule{passphrase.RuleBlocklist}},
+		{"Tq8!absurd-zebra", []passphrase.Rule{passphrase.RuleMnemonicWord}},
+		{"Tq8!zebra-umbrella", nil},
+	} {
+		err := policy.Check(c.passphrase, mnemonic)
+		if c.rules == nil {
+			if err != nil {
+				t.Fatalf("%q: unexpected %v", c.passphrase, err)
+			}
+			continue
+		}
+		pe, ok := err.(*passphrase.PolicyError)
+		if !ok || len(pe.Violations) != len(c.rules) {
+			t.Fatalf("%q: expect %v got %v", c.passphrase, c.rules, err)
+		}
+		for _, r := range c.rules {
+			if !pe.Has(r) {
+				t.Fatalf("%q: expect %v got %v", c.passphrase, r, err)
+			}
+		}
+	}
+}
+
+// go test -run TestWallet_PassphrasePolicy -v
+func TestWallet_PassphrasePolicy(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := wallet.New(&wallet.Config{
+		DataDir:          tmpDir,
+		PassphrasePolicy: &passphrase.Policy{MinLength: 10},
+	})
+	manager.Start()
+	if _, _, err := manager.NewMnemonicAndEntropyStore("123456789"); err == nil {
+		t.Fatal("expect the policy to reject the passphrase")
+	} else if pe, ok := err.(*passphrase.PolicyError); !ok || !pe.Has(passphrase.RuleMinLength) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := manager.RecoverEntropyStoreFromMnemonic("abandon ability able about above absent absorb abstract absurd abuse access accident", ""); err == nil {
+		t.Fatal("expect the policy to reject the empty passphrase")
+	}
+	if files := manager.ListAllEntropyFiles(); len(files) != 0 {
+		t.Fatalf("expect no store got %v", files)
+	}
+}
@@ -0,0 +1,109 @@
+package gvite_demo
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tyler-smith/go-bip39"
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/entropystore"
+	"github.com/vitelabs/go-vite/wallet/hd-bip/derivation"
+	"github.com/vitelabs/go-vite/wallet/testkit"
+	"github.com/vitelabs/go-vite/wallet/walleterrors"
+)
+
+// go test -run TestWallet_PathTemplate -v
+func TestWallet_PathTemplate(t *testing.T) {
+	const template = derivation.PathTemplate("m/44'/coin'/0'/0'/index'")
+	config := testkit.Config("template")
+	manager := wallet.New(config)
+	manager.Start()
+
+	em, err := manager.RecoverEntropyStoreFromMnemonicWithTemplate(testkit.Mnemonic24, testkit.Passphrase, template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed := bip39.NewSeed(testkit.Mnemonic24, "")
+	want := make([]string, 3)
+	for i := range want {
+		k, err := derivation.DeriveForPath(fmt.Sprintf("m/44'/666666'/0'/0'/%d'", i), seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, _ := k.Address()
+		want[i] = addr.String()
+	}
+	defaultAddr, err := entropystore.MnemonicToPrimaryAddr(testkit.Mnemonic24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if em.GetPrimaryAddr().String() != want[0] || em.GetPrimaryAddr() == *defaultAddr {
+		t.Fatalf("unexpected primary address %v", em.GetPrimaryAddr())
+	}
+	if filepath.Base(em.GetEntropyStoreFile()) != want[0] {
+		t.Fatalf("unexpected file %v", em.GetEntropyStoreFile())
+	}
+
+	// the template is in the file, a restarted wallet opens the store with it
+	content, err := config.Storage.Read(em.GetEntropyStoreFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := entropystore.DecryptEntropy(content, testkit.Passphrase); err != nil {
+		t.Fatal(err)
+	}
+	reopened := wallet.New(config)
+	reopened.Start()
+	sm, err := reopened.GetEntropyStoreManager(em.GetEntropyStoreFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tpl, err := sm.PathTemplate(); err != nil || tpl != template {
+		t.Fatalf("unexpected template %v %v", tpl, err)
+	}
+	if err := sm.Unlock(testkit.Passphrase); err != nil {
+		t.Fatal(err)
+	}
+	list, err := sm.ListAddress(0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, info := range list {
+		if info.Address.String() != want[i] {
+			t.Fatalf("address %v is %v", i, info.Address)
+		}
+	}
+	path, _, err := sm.DeriveForIndexPath(2)
+	if err != nil || path != "m/44'/666666'/0'/0'/2'" {
+		t.Fatalf("unexpected path %v %v", path, err)
+	}
+	_, _, index, err := reopened.GlobalFindAddr(list[2].Address)
+	if err != nil || index != 2 {
+		t.Fatalf("expect index 2 got %v %v", index, err)
+	}
+	if _, _, err := sm.FindAddr(*defaultAddr); err != walleterrors.ErrAddressNotFound {
+		t.Fatalf("the default layout must not be searched, got %v", err)
+	}
+
+	// stores without a template keep the default and the file format
+	plain, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24Legal, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tpl, err := plain.PathTemplate(); err != nil || tpl != derivation.ViteAccountTemplate {
+		t.Fatalf("unexpected template %v %v", tpl, err)
+	}
+	content, err = config.Storage.Read(plain.GetEntropyStoreFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "pathTemplate") {
+		t.Fatalf("a default store records its template %s", content)
+	}
+
+	if _, err := manager.RecoverEntropyStoreFromMnemonicWithTemplate(testkit.Mnemonic12, testkit.Passphrase, "m/44'/coin'"); err == nil {
+		t.Fatal("a template without index was accepted")
+	}
+}
@@ -0,0 +1,139 @@
+package gvite_demo
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tyler-smith/go-bip39"
+	"github.com/tyler-smith/go-bip39/wordlists"
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/entropystore"
+	"github.com/vitelabs/go-vite/wallet/hd-bip/derivation"
+	"github.com/vitelabs/go-vite/wallet/recovery"
+)
+
+// go test -run TestRecovery_Mnemonic -v
+func TestRecovery_Mnemonic(t *testing.T) {
+	entropy, err := bip39.NewEntropy(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	primaryAddr, err := entropystore.MnemonicToPrimaryAddr(mnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	words := strings.Fields(mnemonic)
+
+	lost := append([]string(nil), words...)
+	lost[5] = recovery.UnknownWord
+	lost[17] = misspell(lost[17])
+	recovered, err := recovery.RecoverMnemonic(context.Background(), recovery.MnemonicQuery{Words: lost, PrimaryAddr: *primaryAddr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered != mnemonic {
+		t.Fatalf("recovered %v", recovered)
+	}
+
+	swapped := append([]string(nil), words...)
+	swapped[2], swapped[20] = swapped[20], swapped[2]
+	if _, err := recovery.RecoverMnemonic(context.Background(), recovery.MnemonicQuery{Words: swapped, PrimaryAddr: *primaryAddr}); err != recovery.ErrNotFound {
+		t.Fatalf("expect ErrNotFound without swaps got %v", err)
+	}
+	recovered, err = recovery.RecoverMnemonic(context.Background(), recovery.MnemonicQuery{Words: swapped, PrimaryAddr: *primaryAddr, TrySwaps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered != mnemonic {
+		t.Fatalf("recovered %v", recovered)
+	}
+
+	// a store with another layout is found by the primary address of its template
+	const template = derivation.PathTemplate("m/44'/coin'/0'/0'/index'")
+	templatedAddr, err := entropystore.MnemonicToPrimaryAddrWithTemplate(mnemonic, template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered, err = recovery.RecoverMnemonic(context.Background(), recovery.MnemonicQuery{Words: lost, PrimaryAddr: *templatedAddr, Template: template})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered != mnemonic {
+		t.Fatalf("recovered %v", recovered)
+	}
+}
+
+// go test -run TestRecovery_Passphrase -v
+func TestRecovery_Passphrase(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := wallet.New(&wallet.Config{
+		DataDir:              tmpDir,
+		SkipPassphrasePolicy: true,
+	})
+	manager.Start()
+	_, storeManager, err := manager.NewMnemonicAndEntropyStore("vite42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyjson, err := ioutil.ReadFile(storeManager.GetEntropyStoreFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mask, err := recovery.ParseMask("vite?d?d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mask.Len() != 100 || mask.At(42) != "vite42" {
+		t.Fatalf("unexpected mask %v %v", mask.Len(), mask.At(42))
+	}
+	perTry, total, err := recovery.EstimatePassphraseSearch(keyjson, mask, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(perTry, total)
+
+	candidates := recovery.WordList{"vite42", "vite", "Vite42"}
+	checkpoint := filepath.Join(tmpDir, "checkpoint")
+	if err := ioutil.WriteFile(checkpoint, []byte(`{"primaryAddress":"`+storeManager.GetPrimaryAddr().String()+`","candidates":"`+candidates.String()+`","done":1}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	query := recovery.PassphraseQuery{EntropyStore: keyjson, Candidates: candidates, CheckpointFile: checkpoint}
+	if _, err := recovery.RecoverPassphrase(context.Background(), query); err != recovery.ErrNotFound {
+		t.Fatalf("expect the checkpoint to skip the right candidate got %v", err)
+	}
+
+	// a budget below the scrypt memory of one worker still runs one
+	query.CheckpointFile, query.MemoryBudget = "", 1
+	passphrase, err := recovery.RecoverPassphrase(context.Background(), query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passphrase != "vite42" {
+		t.Fatalf("recovered %v", passphrase)
+	}
+}
+
+// misspell changes the last letter of word so that it is no BIP39 word any more, like a typo would
+func misspell(word string) string {
+	valid := make(map[string]bool, len(wordlists.English))
+	for _, w := range wordlists.English {
+		valid[w] = true
+	}
+	for c := 'z'; c >= 'a'; c-- {
+		if typo := word[:len(word)-1] + string(c); !valid[typo] {
+			return typo
+		}
+	}
+	panic("no misspelling of " + word)
+}
@@ -0,0 +1,135 @@
+package gvite_demo
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/entropystore"
+	"github.com/vitelabs/go-vite/wallet/testkit"
+	"github.com/vitelabs/go-vite/wallet/walleterrors"
+)
+
+// go test -run TestWallet_SearchLimit -v
+func TestWallet_SearchLimit(t *testing.T) {
+	config := testkit.Config("searchlimit")
+	config.MaxSearchIndex = 5
+	manager := wallet.New(config)
+	manager.Start()
+
+	// a recovered store uses the window of the config, not the default one
+	small, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if small.MaxSearchIndex() != 5 {
+		t.Fatalf("expect the window of the config got %v", small.MaxSearchIndex())
+	}
+	large, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic12, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, em := range []*entropystore.Manager{small, large} {
+		if err := em.Unlock(testkit.Passphrase); err != nil {
+			t.Fatal(err)
+		}
+	}
+	smallList, err := small.ListAddress(0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	largeList, err := large.ListAddress(0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the window is per store, growing one leaves the other alone
+	if limit, err := manager.GrowSearchLimit(large.GetEntropyStoreFile(), 5); err != nil || limit != 10 {
+		t.Fatalf("expect limit 10 got %v %v", limit, err)
+	}
+	if _, _, _, err := manager.GlobalFindAddr(smallList[7].Address); err != walleterrors.ErrAddressNotFound {
+		t.Fatalf("expect ErrAddressNotFound got %v", err)
+	}
+	if _, _, index, err := manager.GlobalFindAddr(largeList[7].Address); err != nil || index != 7 {
+		t.Fatalf("expect index 7 got %v %v", index, err)
+	}
+
+	// signing keeps the highest used index, the window can not shrink below it
+	if _, _, err := large.SignData(largeList[7].Address, []byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := large.SignData(largeList[2].Address, []byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	if highest := large.HighestUsedIndex(); highest == nil || *highest != 7 {
+		t.Fatalf("expect highest used 7 got %v", highest)
+	}
+	if err := manager.SetSearchLimit(large.GetEntropyStoreFile(), 7); err != walleterrors.ErrSearchLimitTooSmall {
+		t.Fatalf("expect ErrSearchLimitTooSmall got %v", err)
+	}
+	if err := manager.SetSearchLimit(large.GetEntropyStoreFile(), 8); err != nil {
+		t.Fatal(err)
+	}
+
+	// both windows are kept across a restart, even with a smaller config
+	config.MaxSearchIndex = 3
+	reopened := wallet.New(config)
+	reopened.Start()
+	sm, err := reopened.GetEntropyStoreManager(large.GetEntropyStoreFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sm.MaxSearchIndex() != 8 {
+		t.Fatalf("expect the kept window got %v", sm.MaxSearchIndex())
+	}
+	if highest := sm.HighestUsedIndex(); highest == nil || *highest != 7 {
+		t.Fatalf("expect highest used 7 got %v", highest)
+	}
+	if sm, err = reopened.GetEntropyStoreManager(small.GetEntropyStoreFile()); err != nil {
+		t.Fatal(err)
+	}
+	if sm.MaxSearchIndex() != 3 {
+		t.Fatalf("expect the window of the config got %v", sm.MaxSearchIndex())
+	}
+}
+
+// go test -race -run TestWallet_GrowSearchLimitConcurrent -v
+func TestWallet_GrowSearchLimitConcurrent(t *testing.T) {
+	config := testkit.Config("growlimit")
+	config.MaxSearchIndex = 5
+	manager := wallet.New(config)
+	manager.Start()
+	em, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := em.Unlock(testkit.Passphrase); err != nil {
+		t.Fatal(err)
+	}
+	list, err := em.ListAddress(0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := em.GrowSearchLimit(1); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, _, err := em.FindAddr(list[0].Address); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	// no increment is lost
+	if em.MaxSearchIndex() != 15 {
+		t.Fatalf("expect 15 got %v", em.MaxSearchIndex())
+	}
+}
@@ -0,0 +1,154 @@
+package gvite_demo
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/hd-bip/derivation"
+	"github.com/vitelabs/go-vite/wallet/shamir"
+	"github.com/vitelabs/go-vite/wallet/storage"
+	"github.com/vitelabs/go-vite/wallet/testkit"
+)
+
+// go test -run TestShamir_Combine -v
+func TestShamir_Combine(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	shares, err := shamir.Split(secret, 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, picked := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4, 0}} {
+		subset := make([]shamir.Share, 0, len(picked))
+		for _, i := range picked {
+			mnemonic, err := shamir.EncodeMnemonic(shares[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			share, err := shamir.DecodeMnemonic(mnemonic)
+			if err != nil {
+				t.Fatal(err)
+			}
+			subset = append(subset, share)
+		}
+		recovered, err := shamir.Combine(subset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(recovered) != string(secret) {
+			t.Fatalf("%v recovered %x", picked, recovered)
+		}
+	}
+	if _, err := shamir.Combine(shares[:2]); err != shamir.ErrNotEnoughShares {
+		t.Fatalf("expect ErrNotEnoughShares got %v", err)
+	}
+	// a forged share of a lower threshold would hand out its value as the secret
+	for _, threshold := range []byte{0, 1} {
+		forged := shares[0]
+		forged.Threshold = threshold
+		if _, err := shamir.Combine([]shamir.Share{forged}); err != shamir.ErrInvalidThreshold {
+			t.Fatalf("expect ErrInvalidThreshold got %v", err)
+		}
+	}
+
+	mnemonic, _ := shamir.EncodeMnemonic(shares[0])
+	words := strings.Fields(mnemonic)
+	if words[3] == "zoo" {
+		words[3] = "abandon"
+	} else {
+		words[3] = "zoo"
+	}
+	if _, err := shamir.DecodeMnemonic(strings.Join(words, " ")); err != shamir.ErrShareChecksumInvalid {
+		t.Fatal("expect a changed word to fail the checksum")
+	}
+}
+
+// go test -run TestWallet_RecoverFromShares -v
+func TestWallet_RecoverFromShares(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := wallet.New(&wallet.Config{
+		DataDir:              tmpDir,
+		SkipPassphrasePolicy: true,
+	})
+	manager.Start()
+	_, storeManager, err := manager.NewMnemonicAndEntropyStore("123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := storeManager.Unlock("123456"); err != nil {
+		t.Fatal(err)
+	}
+	shares, err := storeManager.SplitEntropy(2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(shares[0])
+
+	custodians := wallet.New(&wallet.Config{Storage: storage.NewMemoryStorage(), SkipPassphrasePolicy: true})
+	custodians.Start()
+	if _, err := custodians.RecoverEntropyStoreFromShares(shares[1:], types.AddressRegister, "abc"); err == nil {
+		t.Fatal("expect primary address mismatch")
+	}
+	em, err := custodians.RecoverEntropyStoreFromShares([]string{shares[2], shares[0]}, storeManager.GetPrimaryAddr(), "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if em.GetPrimaryAddr() != storeManager.GetPrimaryAddr() {
+		t.Fatal("recovered another store")
+	}
+}
+
+// go test -run TestWallet_SplitEntropyReproducible -v
+func TestWallet_SplitEntropyReproducible(t *testing.T) {
+	var splits [2][]string
+	for i := range splits {
+		manager := testkit.NewWallet("split")
+		storeManager, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, testkit.Passphrase)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := storeManager.Unlock(testkit.Passphrase); err != nil {
+			t.Fatal(err)
+		}
+		if splits[i], err = storeManager.SplitEntropy(2, 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if strings.Join(splits[0], ",") != strings.Join(splits[1], ",") {
+		t.Fatalf("the same config randomness gave different shares\n%v\n%v", splits[0], splits[1])
+	}
+}
+
+// go test -run TestWallet_RecoverTemplatedShares -v
+func TestWallet_RecoverTemplatedShares(t *testing.T) {
+	const template = derivation.PathTemplate("m/44'/coin'/0'/0'/index'")
+	manager := testkit.NewWallet("templated shares")
+	storeManager, err := manager.RecoverEntropyStoreFromMnemonicWithTemplate(testkit.Mnemonic24, testkit.Passphrase, template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := storeManager.Unlock(testkit.Passphrase); err != nil {
+		t.Fatal(err)
+	}
+	shares, err := storeManager.SplitEntropy(2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	custodians := testkit.NewWallet("custodians")
+	em, err := custodians.RecoverEntropyStoreFromShares(shares[1:], storeManager.GetPrimaryAddr(), "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := em.PathTemplate(); err != nil || got != template {
+		t.Fatalf("expect template %v got %v %v", template, got, err)
+	}
+	if em.GetPrimaryAddr() != storeManager.GetPrimaryAddr() {
+		t.Fatal("recovered another store")
+	}
+}
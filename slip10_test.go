@@ -0,0 +1,171 @@
+package gvite_demo
+
+import (
+	stded25519 "crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tyler-smith/go-bip39"
+	"github.com/vitelabs/go-vite/wallet/hd-bip/derivation"
+	"github.com/vitelabs/go-vite/wallet/testkit"
+)
+
+// go test -run TestSLIP10_Vectors -v
+func TestSLIP10_Vectors(t *testing.T) {
+	// the ed25519 test vectors of SLIP-10, the public keys are the standard sha512 ed25519 ones
+	for _, v := range []struct {
+		seed, path, chainCode, private, public string
+	}{
+		{"000102030405060708090a0b0c0d0e0f", "m",
+			"90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb",
+			"2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7",
+			"00a4b2856bfec510abab89753fac1ac0e1112364e7d250545963f135f2a33188ed"},
+		{"000102030405060708090a0b0c0d0e0f", "m/0H",
+			"8b59aa11380b624e81507a27fedda59fea6d0b779a778918a2fd3590e16e9c69",
+			"68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3",
+			"008c8a13df77a28f3445213a0f432fde644acaa215fc72dcdf300d5efaa85d350c"},
+		{"000102030405060708090a0b0c0d0e0f", "m/0H/1H",
+			"a320425f77d1b5c2505a6b1b27382b37368ee640e3557c315416801243552f14",
+			"b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2",
+			"001932a5270f335bed617d5b935c80aedb1a35bd9fc1e31acafd5372c30f5c1187"},
+		{"000102030405060708090a0b0c0d0e0f", "m/0H/1H/2H",
+			"2e69929e00b5ab250f49c3fb1c12f252de4fed2c1db88387094a0f8c4c9ccd6c",
+			"92a5b23c0b8a99e37d07df3fb9966917f5d06e02ddbd909c7e184371463e9fc9",
+			"00ae98736566d30ed0e9d2f4486a64bc95740d89c7db33f52121f8ea8f76ff0fc1"},
+		{"000102030405060708090a0b0c0d0e0f", "m/0H/1H/2H/2H",
+			"8f6d87f93d750e0efccda017d662a1b31a266e4a6f5993b15f5c1f07f74dd5cc",
+			"30d1dc7e5fc04c31219ab25a27ae00b50f6fd66622f6e9c913253d6511d1e662",
+			"008abae2d66361c879b900d204ad2cc4984fa2aa344dd7ddc46007329ac76c429c"},
+		{"000102030405060708090a0b0c0d0e0f", "m/0H/1H/2H/2H/1000000000H",
+			"68789923a0cac2cd5a29172a475fe9e0fb14cd6adb5ad98a3fa70333e7afa230",
+			"8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793",
+			"003c24da049451555d51a7014a37337aa4e12d41e485abccfa46b47dfb2af54b7a"},
+		{"fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542", "m",
+			"ef70a74db9c3a5af931b5fe73ed8e1a53464133654fd55e7a66f8570b8e33c3b",
+			"171cb88b1b3c1db25add599712e36245d75bc65a1a5c9e18d76f9f2b1eab4012",
+			"008fe9693f8fa62a4305a140b9764c5ee01e455963744fe18204b4fb948249308a"},
+		{"fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542", "m/0H",
+			"0b78a3226f915c082bf118f83618a618ab6dec793752624cbeb622acb562862d",
+			"1559eb2bbec5790b0c65d8693e4d0875b1747f4970ae8b650486ed7470845635",
+			"0086fab68dcb57aa196c77c5f264f215a112c22a912c10d123b0d03c3c28ef1037"},
+	} {
+		seed, _ := hex.DecodeString(v.seed)
+		key, err := derivation.DerivePath(derivation.SLIP10Ed25519Modifier, v.path, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(key.ChainCode) != v.chainCode || hex.EncodeToString(key.Key) != v.private {
+			t.Fatalf("%v of %v: chain code %x private %x", v.path, v.seed[:8], key.ChainCode, key.Key)
+		}
+		public := stded25519.NewKeyFromSeed(key.Key).Public().(stded25519.PublicKey)
+		if "00"+hex.EncodeToString(public) != v.public {
+			t.Fatalf("%v of %v: public %x", v.path, v.seed[:8], public)
+		}
+	}
+}
+
+// go test -run TestDerivation_Paths -v
+func TestDerivation_Paths(t *testing.T) {
+	for path, want := range map[string]string{
+		"m/44'/666666'/0'": "m/44'/666666'/0'",
+		"m/44h/666666H/7h": "m/44'/666666'/7'",
+		"m/0/1'":           "m/0/1'",
+		"m":                "m",
+	} {
+		indices, err := derivation.ParsePath(path)
+		if err != nil {
+			t.Fatalf("%v : %v", path, err)
+		}
+		if got := derivation.FormatPath(indices); got != want {
+			t.Fatalf("%v formats as %v", path, got)
+		}
+	}
+	for _, path := range []string{"", "44'/0'", "m/", "m//1'", "m/-1'", "m/2147483648'", "m/1x", "m/+1'", "m/1''"} {
+		if _, err := derivation.ParsePath(path); err == nil {
+			t.Fatalf("%q must not parse", path)
+		}
+	}
+
+	// both notations derive the same vite account, a public segment is refused
+	seed := bip39.NewSeed(testkit.Mnemonic24, "")
+	a, err := derivation.DeriveForPath("m/44'/666666'/3'", seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := derivation.DeriveForPath("m/44h/666666h/3h", seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(a.Key) != hex.EncodeToString(b.Key) {
+		t.Fatal("the h notation derives another key")
+	}
+	// DeriveForPath keeps its errors, DerivePath tells a non hardened segment apart
+	for _, bad := range []string{"m", "m/44'/666666'/3", "m/44/666666'/3'"} {
+		if _, err := derivation.DeriveForPath(bad, seed); err != derivation.ErrInvalidPath {
+			t.Fatalf("expect ErrInvalidPath for %v got %v", bad, err)
+		}
+	}
+	if _, err := derivation.DerivePath(derivation.ViteSeedModifier, "m/44'/666666'/3", seed); err != derivation.ErrNoPublicDerivation {
+		t.Fatalf("expect ErrNoPublicDerivation got %v", err)
+	}
+
+	// the registered vite coin gives the accounts of DeriveWithIndex
+	vite, ok := derivation.LookupCoin("vite")
+	if !ok {
+		t.Fatal("vite is not registered")
+	}
+	for i := uint32(0); i < 3; i++ {
+		path, err := vite.IndexPath(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path != fmt.Sprintf(derivation.ViteAccountPathFormat, i) {
+			t.Fatalf("index %v path %v", i, path)
+		}
+		k, err := vite.DeriveWithIndex(i, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := derivation.DeriveWithIndex(i, seed)
+		if hex.EncodeToString(k.Key) != hex.EncodeToString(want.Key) {
+			t.Fatalf("index %v derives another key", i)
+		}
+	}
+}
+
+// go test -run TestDerivation_Templates -v
+func TestDerivation_Templates(t *testing.T) {
+	tpl := derivation.PathTemplate("m/44'/coin'/account'/change'/index'")
+	path, err := tpl.IndexPath(5, map[string]uint32{derivation.CoinVar: 501, "account": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "m/44'/501'/2'/0'/5'" {
+		t.Fatalf("unexpected path %v", path)
+	}
+	if path, _ := derivation.PathTemplate("m/44h/month'/account'").IndexPath(1, nil); path != "m/44h/0'/1'" {
+		t.Fatalf("unexpected path %v", path)
+	}
+	for _, bad := range []derivation.PathTemplate{"", "m", "m/44'/coin'", "m/44'/Account'", "m/44'/account'/account'", "44'/account'",
+		"m/44'/coin'/account'/0/index'", "m/44'/coin'/index"} {
+		if err := bad.Validate(); !errors.Is(err, derivation.ErrInvalidPath) {
+			t.Fatalf("%q must not validate got %v", bad, err)
+		}
+	}
+
+	if err := derivation.RegisterCoin(derivation.Coin{Name: "vite2", Type: derivation.ViteCoinType,
+		SeedModifier: derivation.ViteSeedModifier, Template: derivation.ViteAccountTemplate}); err == nil {
+		t.Fatal("a coin type was registered twice")
+	}
+	if c, ok := derivation.CoinByType(148); !ok || c.Name != "stellar" {
+		t.Fatalf("unexpected coin %+v", c)
+	}
+	coins := derivation.Coins()
+	for i := 1; i < len(coins); i++ {
+		if coins[i-1].Type >= coins[i].Type {
+			t.Fatalf("coins are not sorted %+v", coins)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package gvite_demo
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/storage"
+)
+
+// go test -run TestWallet_MemoryStorage -v
+func TestWallet_MemoryStorage(t *testing.T) {
+	st := storage.NewMemoryStorage()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := st.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager := wallet.New(&wallet.Config{Storage: st, SkipPassphrasePolicy: true})
+	manager.Start()
+	_, storeManager, err := manager.NewMnemonicAndEntropyStore("123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev := <-changes; ev.Op != storage.OpWrite || ev.Name != storeManager.GetEntropyStoreFile() {
+		t.Fatalf("unexpected change %+v", ev)
+	}
+
+	reopened := wallet.New(&wallet.Config{Storage: st, SkipPassphrasePolicy: true})
+	reopened.Start()
+	if err := reopened.Unlock(storeManager.GetPrimaryAddr().Hex(), "123456"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := st.Delete(storeManager.GetEntropyStoreFile()); err != nil {
+		t.Fatal(err)
+	}
+	reopened.RefreshCache()
+	if files := reopened.ListAllEntropyFiles(); len(files) != 0 {
+		t.Fatalf("expect no store got %v", files)
+	}
+}
+
+// go test -run TestWallet_DBStorage -v
+func TestWallet_DBStorage(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbPath := filepath.Join(tmpDir, "wallet.db")
+	db, err := storage.OpenDBStorage(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := wallet.New(&wallet.Config{Storage: db, SkipPassphrasePolicy: true})
+	manager.Start()
+	for i := 0; i < 2; i++ {
+		if _, _, err := manager.NewMnemonicAndEntropyStore("123456"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := ioutil.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expect only the db file got %v files", len(files))
+	}
+
+	db, err = storage.OpenDBStorage(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reopened := wallet.New(&wallet.Config{Storage: db, SkipPassphrasePolicy: true})
+	reopened.Start()
+	if files := reopened.ListAllEntropyFiles(); len(files) != 2 {
+		t.Fatalf("expect 2 stores got %v", files)
+	}
+}
+
+// go test -run TestStorage_FileWatch -v
+func TestStorage_FileWatch(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	fs := storage.NewFileStorage(tmpDir)
+	fs.PollInterval = 5 * time.Millisecond
+	if _, err := fs.Watch(context.Background()); err != storage.ErrWatchNotCancellable {
+		t.Fatalf("expect ErrWatchNotCancellable got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes, err := fs.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := func() storage.Event {
+		select {
+		case ev := <-changes:
+			return ev
+		case <-time.After(time.Second):
+			t.Fatal("expect a change")
+		}
+		return storage.Event{}
+	}
+	if err := fs.Write("store", []byte("content")); err != nil {
+		t.Fatal(err)
+	}
+	if ev := next(); ev.Op != storage.OpWrite || ev.Name != filepath.Join(tmpDir, "store") {
+		t.Fatalf("unexpected change %+v", ev)
+	}
+	if size, err := storage.Size(fs, "store"); err != nil || size != 7 {
+		t.Fatalf("expect size 7 got %v %v", size, err)
+	}
+	if err := fs.Delete("store"); err != nil {
+		t.Fatal(err)
+	}
+	if ev := next(); ev.Op != storage.OpDelete || ev.Name != filepath.Join(tmpDir, "store") {
+		t.Fatalf("unexpected change %+v", ev)
+	}
+
+	// the polling goroutine ends with ctx
+	cancel()
+	for range changes {
+	}
+}
+
+// go test -run TestStorage_OversizedStore -v
+func TestStorage_OversizedStore(t *testing.T) {
+	st := storage.NewMemoryStorage()
+	if err := st.Write("big", make([]byte, 1<<20)); err != nil {
+		t.Fatal(err)
+	}
+	manager := wallet.New(&wallet.Config{Storage: st, SkipPassphrasePolicy: true})
+	manager.Start()
+	if files := manager.ListAllEntropyFiles(); len(files) != 0 {
+		t.Fatalf("expect no store got %v", files)
+	}
+}
@@ -0,0 +1,147 @@
+package gvite_demo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/testkit"
+	"github.com/vitelabs/go-vite/wallet/walleterrors"
+)
+
+// go test -run TestWallet_ResolveStore -v
+func TestWallet_ResolveStore(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	config := testkit.Config("storeid")
+	config.Storage, config.DataDir = nil, tmpDir
+	manager := wallet.New(config)
+	manager.Start()
+	em, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic12, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetStoreName(em.GetEntropyStoreFile(), "treasury"); err != nil {
+		t.Fatal(err)
+	}
+
+	file := em.GetEntropyStoreFile()
+	base := filepath.Base(file)
+	for _, id := range []string{
+		file,
+		filepath.Join(tmpDir, ".", base),
+		tmpDir + string(filepath.Separator) + string(filepath.Separator) + base,
+		base,
+		"." + string(filepath.Separator) + base,
+		filepath.Join("sub", "..", base),
+		em.GetPrimaryAddr().Hex(),
+		"treasury",
+	} {
+		sm, err := manager.ResolveStore(id)
+		if err != nil || sm != em {
+			t.Fatalf("expect %v for %q got %v", file, id, err)
+		}
+	}
+	for _, id := range []string{"", "unknown", "vite_0000000000000000000000000000000000000000a4f3a0cb58", filepath.Join(tmpDir, "sub", base)} {
+		if _, err := manager.ResolveStore(id); err != walleterrors.ErrStoreNotFound {
+			t.Fatalf("expect ErrStoreNotFound for %q got %v", id, err)
+		}
+	}
+
+	// every wallet method takes the same forms
+	if err := manager.Unlock("treasury", testkit.Passphrase); err != nil {
+		t.Fatal(err)
+	}
+	if !manager.IsUnlocked(base) || !manager.IsUnlocked(em.GetPrimaryAddr().Hex()) {
+		t.Fatal("expect the store unlocked")
+	}
+	if err := manager.Lock(em.GetPrimaryAddr().Hex()); err != nil {
+		t.Fatal(err)
+	}
+	if manager.IsUnlocked(file) {
+		t.Fatal("expect the store locked")
+	}
+
+	// a name can not shadow the file or the address of another store
+	if err := manager.SetStoreName(em.GetEntropyStoreFile(), other.GetPrimaryAddr().Hex()); err != walleterrors.ErrStoreNameExists {
+		t.Fatalf("expect ErrStoreNameExists got %v", err)
+	}
+	if err := manager.SetStoreName(em.GetEntropyStoreFile(), filepath.Base(other.GetEntropyStoreFile())); err != walleterrors.ErrStoreNameExists {
+		t.Fatalf("expect ErrStoreNameExists got %v", err)
+	}
+
+	// a relative removal used to delete nothing
+	manager.RemoveEntropyStore(base)
+	if files := manager.ListAllEntropyFiles(); len(files) != 1 || files[0] != other.GetEntropyStoreFile() {
+		t.Fatalf("expect only the other store got %v", files)
+	}
+	if err := manager.AddEntropyStore("." + string(filepath.Separator) + base); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.GetEntropyStoreManager(file); err != nil {
+		t.Fatal(err)
+	}
+	manager.RemoveEntropyStore("treasury")
+	manager.RemoveEntropyStore(other.GetPrimaryAddr().Hex())
+	if files := manager.ListAllEntropyFiles(); len(files) != 0 {
+		t.Fatalf("expect no store got %v", files)
+	}
+}
+
+// go test -run TestWallet_ResolveStoreRelativeDataDir -v
+func TestWallet_ResolveStoreRelativeDataDir(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Mkdir("data", 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	config := testkit.Config("storeid")
+	config.Storage, config.DataDir = nil, "data"
+	manager := wallet.New(config)
+	manager.Start()
+	em, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := em.GetEntropyStoreFile()
+	if !filepath.IsAbs(file) {
+		t.Fatalf("expect an absolute path got %v", file)
+	}
+	for _, id := range []string{file, filepath.Base(file), filepath.Join("..", "data", filepath.Base(file))} {
+		if sm, err := manager.ResolveStore(id); err != nil || sm != em {
+			t.Fatalf("expect %v for %q got %v", file, id, err)
+		}
+	}
+
+	// a restarted wallet lists the store under the same path
+	restarted := wallet.New(config)
+	restarted.Start()
+	if files := restarted.ListAllEntropyFiles(); len(files) != 1 || files[0] != file {
+		t.Fatalf("expect %v got %v", file, files)
+	}
+	restarted.RemoveEntropyStore(file)
+	if files := restarted.ListAllEntropyFiles(); len(files) != 0 {
+		t.Fatalf("expect no store got %v", files)
+	}
+}
@@ -0,0 +1,105 @@
+package gvite_demo
+
+import (
+	"testing"
+
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/entropystore"
+	"github.com/vitelabs/go-vite/wallet/testkit"
+)
+
+// go test -run TestTestkit_GoldenStores -v
+// GOLDEN_UPDATE=1 go test -run TestTestkit_GoldenStores rewrites testdata
+func TestTestkit_GoldenStores(t *testing.T) {
+	files := goldenStores(t)
+	for name, content := range files {
+		testkit.Golden(t, name, content)
+	}
+	// a second wallet with the same seed writes the very same bytes
+	for name, content := range goldenStores(t) {
+		if string(content) != string(files[name]) {
+			t.Fatalf("%v is not reproducible", name)
+		}
+	}
+}
+
+func goldenStores(t *testing.T) map[string][]byte {
+	config := testkit.Config("golden")
+	manager := wallet.New(config)
+	manager.Start()
+	read := func(filename string) []byte {
+		b, err := config.Storage.Read(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	files := make(map[string][]byte)
+
+	em, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files["entropystore.json"] = read(em.GetEntropyStoreFile())
+	files["metadata.json"] = read(entropystore.MetadataFileName(em.GetEntropyStoreFile()))
+	archive, err := manager.ExportBackup(testkit.BackupPassphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files["backup.json"] = archive
+
+	slotted, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24Legal, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recoveryKey, _, err := slotted.AddRecoveryKeySlot(entropystore.Credentials{Passphrase: testkit.Passphrase}, "paper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := slotted.Unlock(recoveryKey); err != nil {
+		t.Fatal(err)
+	}
+	files["keyslots.json"] = read(slotted.GetEntropyStoreFile())
+
+	mnemonic, _, hidden, err := manager.NewHiddenEntropyStore(testkit.Passphrase, "duress")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files["hidden.json"] = append(read(hidden.GetEntropyStoreFile()), "\n"+hidden.GetEntropyStoreFile()+"\n"+mnemonic...)
+	return files
+}
+
+// go test -run TestTestkit_FastWallet -v
+func TestTestkit_FastWallet(t *testing.T) {
+	config := testkit.Config("fast")
+	manager := wallet.New(config)
+	manager.Start()
+	mnemonic, em, err := manager.NewMnemonicAndEntropyStore(testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other, _, _ := testkit.NewWallet("fast").NewMnemonicAndEntropyStore(testkit.Passphrase); other != mnemonic {
+		t.Fatalf("the same seed gave %v and %v", mnemonic, other)
+	}
+	if other, _, _ := testkit.NewWallet("other").NewMnemonicAndEntropyStore(testkit.Passphrase); other == mnemonic {
+		t.Fatal("different seeds gave the same mnemonic")
+	}
+
+	content, err := config.Storage.Read(em.GetEntropyStoreFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _, _, err := entropystore.ScryptParams(content); err != nil || n != testkit.FastScryptN {
+		t.Fatalf("expect scrypt n %v got %v %v", testkit.FastScryptN, n, err)
+	}
+	if err := em.Unlock(testkit.Passphrase); err != nil {
+		t.Fatal(err)
+	}
+	md, err := em.Metadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md.CreatedAt != testkit.Epoch.Unix() {
+		t.Fatalf("expect the testkit clock got %v", md.CreatedAt)
+	}
+}
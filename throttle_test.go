@@ -0,0 +1,190 @@
+package gvite_demo
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/entropystore"
+	"github.com/vitelabs/go-vite/wallet/testkit"
+	"github.com/vitelabs/go-vite/wallet/walleterrors"
+)
+
+// go test -run TestWallet_UnlockThrottle -v
+func TestWallet_UnlockThrottle(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := &entropystore.ThrottlePolicy{FreeAttempts: 1, BaseDelay: time.Hour, LockoutAfter: 3}
+	manager := wallet.New(&wallet.Config{
+		DataDir:              tmpDir,
+		Throttle:             policy,
+		SkipPassphrasePolicy: true,
+	})
+	manager.Start()
+	_, storeManager, err := manager.NewMnemonicAndEntropyStore("123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := storeManager.GetEntropyStoreFile()
+
+	for i := 0; i < 2; i++ {
+		if err := manager.Unlock(store, "wrong"); err != walleterrors.ErrDecryptEntropy {
+			t.Fatalf("expect ErrDecryptEntropy got %v", err)
+		}
+	}
+	if _, _, err := storeManager.SignDataWithPassphrase(storeManager.GetPrimaryAddr(), "123456", []byte("vite")); err != walleterrors.ErrUnlockThrottled {
+		t.Fatalf("expect ErrUnlockThrottled got %v", err)
+	}
+
+	// the failures survive a restart
+	restarted := wallet.New(&wallet.Config{
+		DataDir:              tmpDir,
+		Throttle:             policy,
+		SkipPassphrasePolicy: true,
+	})
+	restarted.Start()
+	state, next, err := restarted.GetUnlockThrottle(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Failures != 2 || time.Until(next) < 59*time.Minute {
+		t.Fatalf("unexpected state %+v next %v", state, next)
+	}
+	if err := restarted.Unlock(store, "123456"); err != walleterrors.ErrUnlockThrottled {
+		t.Fatalf("expect ErrUnlockThrottled got %v", err)
+	}
+
+	sub := restarted.Subscribe(context.Background(), wallet.EventFilter{Types: []wallet.EventType{wallet.LockedOut}}, 0)
+	defer sub.Unsubscribe()
+	policy.BaseDelay = 0
+	if err := restarted.Unlock(store, "wrong"); err != walleterrors.ErrDecryptEntropy {
+		t.Fatalf("expect ErrDecryptEntropy got %v", err)
+	}
+	if ev := <-sub.C; ev.EntropyStoreFile != store {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+	if err := restarted.Unlock(store, "123456"); err != walleterrors.ErrLockedOut {
+		t.Fatalf("expect ErrLockedOut got %v", err)
+	}
+
+	if err := restarted.ResetUnlockThrottle(store); err != nil {
+		t.Fatal(err)
+	}
+	if err := restarted.Unlock(store, "123456"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// go test -run TestWallet_GlobalFindThrottle -v
+func TestWallet_GlobalFindThrottle(t *testing.T) {
+	config := testkit.Config("globalfind")
+	config.Throttle = &entropystore.ThrottlePolicy{FreeAttempts: 1, BaseDelay: time.Hour, LockoutAfter: 1}
+	manager := wallet.New(config)
+	manager.Start()
+	mine, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic12, "another passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mine.Unlock(testkit.Passphrase); err != nil {
+		t.Fatal(err)
+	}
+	list, err := mine.ListAddress(0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mine.Lock()
+
+	// probing by a derived address does not count against the store that does not hold it
+	for i := 0; i < 3; i++ {
+		path, _, index, err := manager.GlobalFindAddrWithPassphrase(list[1].Address, testkit.Passphrase)
+		if err != nil || path != mine.GetEntropyStoreFile() || index != 1 {
+			t.Fatalf("expect index 1 of %v got %v %v %v", mine.GetEntropyStoreFile(), path, index, err)
+		}
+	}
+	// a guess opening no store is throttled by the wallet
+	if _, _, _, err := manager.GlobalFindAddrWithPassphrase(list[1].Address, "wrong"); err != walleterrors.ErrDecryptEntropy {
+		t.Fatalf("expect ErrDecryptEntropy got %v", err)
+	}
+	if _, _, _, err := manager.GlobalFindAddrWithPassphrase(list[1].Address, "wrong"); err != walleterrors.ErrDecryptEntropy {
+		t.Fatalf("expect ErrDecryptEntropy got %v", err)
+	}
+	if _, _, _, err := manager.GlobalFindAddrWithPassphrase(list[1].Address, testkit.Passphrase); err != walleterrors.ErrUnlockThrottled {
+		t.Fatalf("expect ErrUnlockThrottled got %v", err)
+	}
+	// the guesses survive a restart
+	restarted := wallet.New(config)
+	restarted.Start()
+	if _, _, _, err := restarted.GlobalFindAddrWithPassphrase(list[1].Address, testkit.Passphrase); err != walleterrors.ErrUnlockThrottled {
+		t.Fatalf("expect ErrUnlockThrottled got %v", err)
+	}
+	if files := restarted.ListAllEntropyFiles(); len(files) != 2 {
+		t.Fatalf("unexpected stores %v", files)
+	}
+	for _, em := range []*entropystore.Manager{mine, other} {
+		if state, err := em.ThrottleState(); err != nil || state.Failures != 0 {
+			t.Fatalf("expect no failure of the store got %+v %v", state, err)
+		}
+	}
+}
+
+// go test -run TestWallet_ThrottleCountsGuessesOnly -v
+func TestWallet_ThrottleCountsGuessesOnly(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyfile := filepath.Join(tmpDir, "keyfile")
+	if err := ioutil.WriteFile(keyfile, []byte("some random bytes"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	config := testkit.Config("guesses only")
+	config.Throttle = &entropystore.ThrottlePolicy{BaseDelay: time.Hour}
+	manager := wallet.New(config)
+	manager.Start()
+	_, storeManager, err := manager.NewMnemonicAndEntropyStoreWithKeyfile(testkit.Passphrase, keyfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := storeManager.GetEntropyStoreFile()
+	sub := manager.Subscribe(context.Background(), wallet.EventFilter{Types: []wallet.EventType{wallet.UnlockFailed}}, 0)
+	defer sub.Unsubscribe()
+
+	// a missing keyfile is no guess
+	for i := 0; i < 2; i++ {
+		if err := manager.Unlock(store, testkit.Passphrase); err != walleterrors.ErrKeyfileRequired {
+			t.Fatalf("expect ErrKeyfileRequired got %v", err)
+		}
+	}
+	if state, err := storeManager.ThrottleState(); err != nil || state.Failures != 0 {
+		t.Fatalf("expect no failure got %+v %v", state, err)
+	}
+	if len(sub.C) != 0 {
+		t.Fatalf("expect no failed unlock event got %v", len(sub.C))
+	}
+	if err := manager.UnlockWithKeyfile(store, testkit.Passphrase, keyfile, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// go test -run TestThrottlePolicy_Uncapped -v
+func TestThrottlePolicy_Uncapped(t *testing.T) {
+	policy := entropystore.ThrottlePolicy{BaseDelay: time.Hour}
+	last := testkit.Epoch.UnixNano()
+	previous := time.Unix(0, last)
+	for _, failures := range []int{1, 2, 40, 64, 1000} {
+		next := entropystore.ThrottleState{Failures: failures, LastFailure: last}.NextAttempt(policy)
+		if next.Before(previous) {
+			t.Fatalf("the delay of %v failures shrinks to %v", failures, next.Sub(time.Unix(0, last)))
+		}
+		previous = next
+	}
+}
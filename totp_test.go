@@ -0,0 +1,168 @@
+package gvite_demo
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/entropystore"
+	"github.com/vitelabs/go-vite/wallet/totp"
+	"github.com/vitelabs/go-vite/wallet/walleterrors"
+)
+
+// go test -run TestTOTP_RFC6238 -v
+func TestTOTP_RFC6238(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	vectors := map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1111111111:  "14050471",
+		1234567890:  "89005924",
+		2000000000:  "69279037",
+		20000000000: "65353130",
+	}
+	for unix, code := range vectors {
+		if got := totp.Code(secret, time.Unix(unix, 0), 8, totp.DefaultPeriod); got != code {
+			t.Fatalf("%v: expect %v got %v", unix, code, got)
+		}
+	}
+}
+
+// go test -run TestWallet_TOTP -v
+func TestWallet_TOTP(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := wallet.New(&wallet.Config{
+		DataDir:              tmpDir,
+		SkipPassphrasePolicy: true,
+	})
+	manager.Start()
+	_, storeManager, err := manager.NewMnemonicAndEntropyStore("123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := storeManager.GetEntropyStoreFile()
+	now := time.Unix(1600000000, 0)
+	storeManager.SetClock(func() time.Time { return now })
+
+	enrollment, err := manager.EnrollTOTP(store, "123456", "vite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err := totp.DecodeSecret(enrollment.Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Unlock(store, "123456"); err != walleterrors.ErrTOTPRequired {
+		t.Fatalf("expect ErrTOTPRequired got %v", err)
+	}
+	code := totp.Code(secret, now, totp.DefaultDigits, totp.DefaultPeriod)
+	if err := manager.UnlockWithTOTP(store, "123456", code, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.UnlockWithTOTP(store, "123456", code, 0); err != walleterrors.ErrInvalidTOTP {
+		t.Fatalf("expect a replayed code to fail got %v", err)
+	}
+	now = now.Add(totp.DefaultPeriod)
+	if err := manager.UnlockWithTOTP(store, "123456", totp.Code(secret, now, totp.DefaultDigits, totp.DefaultPeriod), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	unspent, err := storeManager.Metadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.UnlockWithTOTP(store, "123456", enrollment.RecoveryCodes[3], 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.UnlockWithTOTP(store, "123456", enrollment.RecoveryCodes[3], 0); err != walleterrors.ErrInvalidTOTP {
+		t.Fatalf("expect a spent recovery code to fail got %v", err)
+	}
+	// the spent code and the older counter can not be put back into the metadata
+	spent, err := storeManager.Metadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, state := range []*entropystore.TOTPState{
+		{CipherText: spent.TOTP.CipherText, Nonce: spent.TOTP.Nonce, Digits: spent.TOTP.Digits, Period: spent.TOTP.Period,
+			LastCounter: spent.TOTP.LastCounter, RecoveryCodes: unspent.TOTP.RecoveryCodes},
+		{CipherText: spent.TOTP.CipherText, Nonce: spent.TOTP.Nonce, Digits: spent.TOTP.Digits, Period: spent.TOTP.Period,
+			LastCounter: spent.TOTP.LastCounter - 1, RecoveryCodes: spent.TOTP.RecoveryCodes},
+	} {
+		if err := storeManager.UpdateMetadata(func(md *entropystore.Metadata) error { md.TOTP = state; return nil }); err != nil {
+			t.Fatal(err)
+		}
+		if err := manager.UnlockWithTOTP(store, "123456", enrollment.RecoveryCodes[3], 0); err != walleterrors.ErrInvalidTOTP {
+			t.Fatalf("expect a tampered state to fail got %v", err)
+		}
+	}
+	if err := storeManager.UpdateMetadata(func(md *entropystore.Metadata) error { md.TOTP = spent.TOTP; return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if left, err := storeManager.RemainingRecoveryCodes(); err != nil || left != len(enrollment.RecoveryCodes)-1 {
+		t.Fatalf("unexpected recovery codes left %v %v", left, err)
+	}
+
+	// a wrong passphrase fails like a wrong code
+	now = now.Add(totp.DefaultPeriod)
+	code = totp.Code(secret, now, totp.DefaultDigits, totp.DefaultPeriod)
+	if err := manager.UnlockWithTOTP(store, "wrong", code, 0); err != walleterrors.ErrInvalidTOTP {
+		t.Fatalf("expect ErrInvalidTOTP got %v", err)
+	}
+
+	// the passphrase alone can not add a slot or change itself
+	if _, err := manager.AddPassphraseSlot(store, "123456", "second", "spare"); err != walleterrors.ErrTOTPRequired {
+		t.Fatalf("expect ErrTOTPRequired got %v", err)
+	}
+	if err := manager.ChangePassphrase(store, "123456", "", "654321", ""); err != walleterrors.ErrTOTPRequired {
+		t.Fatalf("expect ErrTOTPRequired got %v", err)
+	}
+	if _, err := manager.AddPassphraseSlotWithCredentials(store, entropystore.Credentials{Passphrase: "123456", OTP: code}, "second", "spare"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.DisableTOTP(store, "123456", enrollment.RecoveryCodes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Unlock(store, "123456"); err != nil {
+		t.Fatal(err)
+	}
+
+	// a store with TOTP is deleted with a code
+	_, other, err := manager.NewMnemonicAndEntropyStore("123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.SetClock(func() time.Time { return now })
+	otherEnrollment, err := manager.EnrollTOTP(other.GetEntropyStoreFile(), "123456", "vite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	confirm := wallet.DeleteConfirmation{Passphrase: "123456"}
+	if _, err := manager.DeleteEntropyStore(other.GetEntropyStoreFile(), confirm); err != walleterrors.ErrTOTPRequired {
+		t.Fatalf("expect ErrTOTPRequired got %v", err)
+	}
+	confirm.OTP = otherEnrollment.RecoveryCodes[0]
+	if _, err := manager.DeleteEntropyStore(other.GetEntropyStoreFile(), confirm); err != nil {
+		t.Fatal(err)
+	}
+
+	// removing the enrollment from the metadata sidecar does not turn TOTP off
+	if enrollment, err = manager.EnrollTOTP(store, "123456", "vite"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(entropystore.MetadataFileName(store)); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Unlock(store, "123456"); err != walleterrors.ErrTOTPRequired {
+		t.Fatalf("expect ErrTOTPRequired got %v", err)
+	}
+	if err := manager.UnlockWithTOTP(store, "123456", enrollment.RecoveryCodes[0], 0); err != walleterrors.ErrInvalidTOTP {
+		t.Fatalf("expect ErrInvalidTOTP got %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package gvite_demo
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/storage"
+	"github.com/vitelabs/go-vite/wallet/testkit"
+	"github.com/vitelabs/go-vite/wallet/walleterrors"
+)
+
+// go test -run TestWallet_DeleteEntropyStore -v
+func TestWallet_DeleteEntropyStore(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	config := testkit.Config("trash")
+	config.Storage, config.DataDir = nil, tmpDir
+	manager := wallet.New(config)
+	manager.Start()
+	em, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, addr := em.GetEntropyStoreFile(), em.GetPrimaryAddr()
+	if err := manager.SetStoreName(file, "cold"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, confirm := range []wallet.DeleteConfirmation{{}, {PrimaryAddress: addr.Hex()[:20]}} {
+		if _, err := manager.DeleteEntropyStore("cold", confirm); err != walleterrors.ErrDeleteNotConfirmed {
+			t.Fatalf("expect ErrDeleteNotConfirmed got %v", err)
+		}
+	}
+	if _, err := manager.DeleteEntropyStore("cold", wallet.DeleteConfirmation{Passphrase: "wrong"}); err != walleterrors.ErrDecryptEntropy {
+		t.Fatalf("expect ErrDecryptEntropy got %v", err)
+	}
+	entry, err := manager.DeleteEntropyStore("cold", wallet.DeleteConfirmation{PrimaryAddress: addr.Hex()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.DeletedFrom != file || entry.PrimaryAddr == nil || *entry.PrimaryAddr != addr ||
+		!entry.ExpiresAt.Equal(entry.DeletedAt.Add(wallet.DefaultTrashRetention)) {
+		t.Fatalf("unexpected trash entry %+v", entry)
+	}
+	if filepath.Dir(entry.File) != filepath.Join(tmpDir, wallet.TrashDirName) {
+		t.Fatalf("expect the store in the trash dir got %v", entry.File)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Fatalf("expect the store file removed got %v", err)
+	}
+	if files := manager.ListAllEntropyFiles(); len(files) != 0 {
+		t.Fatalf("expect no store got %v", files)
+	}
+
+	// the trash is not loaded as stores
+	manager = wallet.New(config)
+	manager.Start()
+	if files := manager.ListAllEntropyFiles(); len(files) != 0 {
+		t.Fatalf("expect no store got %v", files)
+	}
+	entries, err := manager.ListTrash()
+	if err != nil || len(entries) != 1 || entries[0].File != entry.File {
+		t.Fatalf("expect the trash entry got %v %v", entries, err)
+	}
+
+	// the restored store keeps its metadata
+	restored, err := manager.RestoreEntropyStore(filepath.Base(entry.File))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.GetEntropyStoreFile() != file {
+		t.Fatalf("expect %v got %v", file, restored.GetEntropyStoreFile())
+	}
+	if err := manager.Unlock("cold", testkit.Passphrase); err != nil {
+		t.Fatal(err)
+	}
+	if md, err := manager.GetStoreMetadata(file); err != nil || md.DeletedFrom != "" || md.DeletedAt != 0 {
+		t.Fatalf("unexpected metadata %+v %v", md, err)
+	}
+	if entries, err := manager.ListTrash(); err != nil || len(entries) != 0 {
+		t.Fatalf("expect an empty trash got %v %v", entries, err)
+	}
+
+	// a purged store is gone for good
+	if entry, err = manager.DeleteEntropyStore(file, wallet.DeleteConfirmation{Passphrase: testkit.Passphrase}); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.PurgeEntropyStore(entry.File); err != nil {
+		t.Fatal(err)
+	}
+	if files, err := ioutil.ReadDir(filepath.Join(tmpDir, wallet.TrashDirName)); err != nil || len(files) != 0 {
+		t.Fatalf("expect an empty trash dir got %v %v", files, err)
+	}
+	if _, err := manager.RestoreEntropyStore(entry.File); err != walleterrors.ErrTrashEntryNotFound {
+		t.Fatalf("expect ErrTrashEntryNotFound got %v", err)
+	}
+}
+
+// go test -run TestWallet_TrashRetention -v
+func TestWallet_TrashRetention(t *testing.T) {
+	now := testkit.Epoch
+	config := testkit.Config("trash")
+	config.Clock = func() time.Time { return now }
+	config.TrashRetention = 24 * time.Hour
+	manager := wallet.New(config)
+	manager.Start()
+	em, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := manager.DeleteEntropyStore(em.GetPrimaryAddr().Hex(), wallet.DeleteConfirmation{Passphrase: testkit.Passphrase})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a store of the same file blocks the restore
+	if _, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, testkit.Passphrase); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.RestoreEntropyStore(entry.File); err != walleterrors.ErrStoreFileExists {
+		t.Fatalf("expect ErrStoreFileExists got %v", err)
+	}
+
+	now = now.Add(23 * time.Hour)
+	if purged, err := manager.PurgeExpiredTrash(); err != nil || len(purged) != 0 {
+		t.Fatalf("expect nothing purged got %v %v", purged, err)
+	}
+	// the next start purges the expired store
+	now = now.Add(time.Hour)
+	manager = wallet.New(config)
+	manager.Start()
+	if entries, err := manager.ListTrash(); err != nil || len(entries) != 0 {
+		t.Fatalf("expect an empty trash got %v %v", entries, err)
+	}
+	if storage.Exists(config.Storage, entry.File) {
+		t.Fatal("expect the trash entry shredded")
+	}
+	if files := manager.ListAllEntropyFiles(); len(files) != 1 {
+		t.Fatalf("expect the recovered store got %v", files)
+	}
+}
+
+// go test -run TestStorage_Shred -v
+func TestStorage_Shred(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	db, err := storage.OpenDBStorage(filepath.Join(tmpDir, "wallet.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, st := range []storage.Storage{storage.NewFileStorage(tmpDir), storage.NewMemoryStorage(), db} {
+		if err := st.Write("store", []byte("secret")); err != nil {
+			t.Fatal(err)
+		}
+		if err := storage.Shred(st, "store"); err != nil {
+			t.Fatal(err)
+		}
+		if storage.Exists(st, "store") {
+			t.Fatal("expect the entry removed")
+		}
+		if err := storage.Shred(st, "store"); err != storage.ErrNotExist {
+			t.Fatalf("expect ErrNotExist got %v", err)
+		}
+	}
+}
+
+// go test -run TestWallet_PurgeDBStorage -v
+func TestWallet_PurgeDBStorage(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	dbPath := filepath.Join(tmpDir, "wallet.db")
+	db, err := storage.OpenDBStorage(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := testkit.Config("purgedb")
+	config.Storage = db
+	manager := wallet.New(config)
+	manager.Start()
+	em, err := manager.RecoverEntropyStoreFromMnemonic(testkit.Mnemonic24, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := db.Read(em.GetEntropyStoreFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := manager.DeleteEntropyStore(em.GetEntropyStoreFile(), wallet.DeleteConfirmation{Passphrase: testkit.Passphrase})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the file holding the trash entry is overwritten where it lies, not only replaced by a new file
+	f, err := os.Open(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	encoded := []byte(base64.StdEncoding.EncodeToString(content))
+	if before, _ := ioutil.ReadFile(dbPath); !bytes.Contains(before, encoded) {
+		t.Fatal("expect the trash entry in the database file")
+	}
+	if err := manager.PurgeEntropyStore(entry.File); err != nil {
+		t.Fatal(err)
+	}
+	old, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(old, encoded) {
+		t.Fatal("the replaced database file still holds the store")
+	}
+	if storage.Exists(db, entry.File) {
+		t.Fatal("expect the trash entry removed")
+	}
+}
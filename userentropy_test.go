@@ -0,0 +1,87 @@
+package gvite_demo
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/tyler-smith/go-bip39"
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/entropystore"
+	"github.com/vitelabs/go-vite/wallet/userentropy"
+)
+
+// go test -run TestUserEntropy -v
+func TestUserEntropy(t *testing.T) {
+	rolls := strings.Repeat("3162554", 15) // 105 rolls
+	dice := userentropy.Source{Kind: userentropy.Dice, Input: rolls}
+
+	entropy, bits, err := userentropy.Pure(32, dice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(bits-105*math.Log2(6)) > 1e-9 {
+		t.Fatalf("unexpected bits %v", bits)
+	}
+	// printf 'dice:3162554...3162554\n' | sha256sum
+	expected, _ := hex.DecodeString("4a90ebc056a21d61ffce196b66ebfdc32cc44b8556f57f7500aa83282853ef6b")
+	if !bytes.Equal(entropy, expected) {
+		t.Fatalf("pure mode is not reproducible %x %x", entropy, expected)
+	}
+	spaced := userentropy.Source{Kind: userentropy.Dice, Input: strings.Join(strings.Split(rolls, ""), " ")}
+	if again, _, _ := userentropy.Pure(32, spaced); !bytes.Equal(again, entropy) {
+		t.Fatal("separators must not change the entropy")
+	}
+
+	// the same symbols of another kind, or split differently, give another entropy
+	asHex, _, err := userentropy.Pure(32, userentropy.Source{Kind: userentropy.Hex, Input: rolls})
+	if err != nil {
+		t.Fatal(err)
+	}
+	split, _, err := userentropy.Pure(32, userentropy.Source{Kind: userentropy.Dice, Input: rolls[:50]}, userentropy.Source{Kind: userentropy.Dice, Input: rolls[50:]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(asHex, entropy) || bytes.Equal(split, entropy) {
+		t.Fatal("the kind and the sources must be part of the entropy")
+	}
+
+	if _, _, err := userentropy.Pure(32, userentropy.Source{Kind: userentropy.Dice, Input: rolls[:90]}); err != userentropy.ErrNotEnoughEntropy {
+		t.Fatalf("expect ErrNotEnoughEntropy got %v", err)
+	}
+	if _, _, err := userentropy.Mix(32, userentropy.Source{Kind: userentropy.Dice, Input: "1237"}); err == nil {
+		t.Fatal("expect an invalid roll to fail")
+	}
+	coin := userentropy.Source{Kind: userentropy.Coin, Input: "HTTH HHTT"}
+	mixed1, bits, err := userentropy.Mix(32, coin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mixed2, _, _ := userentropy.Mix(32, coin)
+	if bits != 8 || bytes.Equal(mixed1, mixed2) {
+		t.Fatalf("unexpected mix %v %x %x", bits, mixed1, mixed2)
+	}
+
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := wallet.New(&wallet.Config{
+		DataDir:              tmpDir,
+		SkipPassphrasePolicy: true,
+	})
+	manager.Start()
+	mnemonic, _, storeManager, err := manager.NewMnemonicAndEntropyStoreWithUserEntropy("123456", []userentropy.Source{dice}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expectedMnemonic, _ := bip39.NewMnemonic(expected); mnemonic != expectedMnemonic {
+		t.Fatalf("unexpected mnemonic %v", mnemonic)
+	}
+	if md, err := storeManager.Metadata(); err != nil || md.Source != entropystore.SourceUserEntropy || !md.BackupUnverified {
+		t.Fatalf("unexpected metadata %+v %v", md, err)
+	}
+}
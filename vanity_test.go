@@ -0,0 +1,135 @@
+package gvite_demo
+
+import (
+	"context"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/wallet/storage"
+	"github.com/vitelabs/go-vite/wallet/testkit"
+	"github.com/vitelabs/go-vite/wallet/vanity"
+	"github.com/vitelabs/go-vite/wallet/walleterrors"
+)
+
+// go test -run TestVanity_RawKey -v
+func TestVanity_RawKey(t *testing.T) {
+	result, err := vanity.Search(context.Background(), vanity.Config{Prefix: "vite_0", Suffix: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hex := result.Address.Hex()
+	if !strings.HasPrefix(hex, "vite_0") || !strings.HasSuffix(hex, "a") {
+		t.Fatalf("unexpected address %v", hex)
+	}
+	t.Log(hex, result.Tried)
+
+	st := storage.NewMemoryStorage()
+	if err := vanity.StoreRawKey(st, nil, hex, result.PrivateKey, "123456"); err != nil {
+		t.Fatal(err)
+	}
+	key, err := vanity.LoadRawKey(st, hex, "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if types.PrikeyToAddress(key) != result.Address {
+		t.Fatal("loaded another key")
+	}
+}
+
+// go test -run TestVanity_Cancel -v
+func TestVanity_Cancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	var last vanity.Progress
+	_, err := vanity.Search(ctx, vanity.Config{
+		Prefix:           "ffffffffffff",
+		ProgressInterval: 50 * time.Millisecond,
+		Progress: func(p vanity.Progress) {
+			last = p
+		},
+	})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expect deadline exceeded got %v", err)
+	}
+	if last.Tried == 0 || last.ETA == 0 {
+		t.Fatalf("unexpected progress %+v", last)
+	}
+}
+
+// go test -run TestWallet_NewVanityEntropyStore -v
+func TestWallet_NewVanityEntropyStore(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := wallet.New(&wallet.Config{
+		DataDir:              tmpDir,
+		SkipPassphrasePolicy: true,
+	})
+	manager.Start()
+	_, em, index, err := manager.NewVanityEntropyStore(context.Background(), vanity.Config{Regexp: regexp.MustCompile("^vite_[0-9]{2}")}, "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := em.Unlock("123456"); err != nil {
+		t.Fatal(err)
+	}
+	list, err := em.ListAddress(index, index+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile("^vite_[0-9]{2}").MatchString(list[0].Hex()) {
+		t.Fatalf("unexpected address %v", list[0].Hex())
+	}
+	if _, found, err := em.FindAddr(list[0].Address); err != nil || found != index {
+		t.Fatalf("the store can not find its vanity address %v", err)
+	}
+}
+
+// go test -run TestWallet_NewVanityRawKey -v
+func TestWallet_NewVanityRawKey(t *testing.T) {
+	config := testkit.Config("vanity raw key")
+	manager := wallet.New(config)
+	manager.Start()
+	addr, file, err := manager.NewVanityRawKey(context.Background(), vanity.Config{Prefix: "vite_0", Workers: 1}, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(addr.Hex(), "vite_0") {
+		t.Fatalf("unexpected address %v", addr.Hex())
+	}
+
+	// the key only depends on the randomness of the wallet
+	other := testkit.NewWallet("vanity raw key")
+	if otherAddr, _, err := other.NewVanityRawKey(context.Background(), vanity.Config{Prefix: "vite_0", Workers: 1}, testkit.Passphrase); err != nil || otherAddr != addr {
+		t.Fatalf("expect the same key from the same seed got %v %v", otherAddr, err)
+	}
+
+	// a restarted wallet indexes the raw key besides the entropy stores
+	restarted := wallet.New(config)
+	restarted.Start()
+	if keys := restarted.ListRawKeys(); len(keys) != 1 || keys[file] != addr {
+		t.Fatalf("unexpected raw keys %v", keys)
+	}
+	if files := restarted.ListAllEntropyFiles(); len(files) != 0 {
+		t.Fatalf("the raw key is listed as an entropy store %v", files)
+	}
+	key, err := restarted.LoadRawKey(addr, testkit.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if types.PrikeyToAddress(key) != addr {
+		t.Fatal("loaded another key")
+	}
+	if _, err := restarted.LoadRawKey(addr, "wrong"); err != walleterrors.ErrDecryptEntropy {
+		t.Fatalf("expect ErrDecryptEntropy got %v", err)
+	}
+	if err := restarted.AddRawKey(file + ".missing"); err != walleterrors.ErrNotRawKey {
+		t.Fatalf("expect ErrNotRawKey got %v", err)
+	}
+}
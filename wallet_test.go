@@ -15,7 +15,8 @@ func TestWallet_RandomAddr(t *testing.T) {
 	}
 	t.Log(tmpDir)
 	manager := wallet.New(&wallet.Config{
-		DataDir: tmpDir,
+		DataDir:              tmpDir,
+		SkipPassphrasePolicy: true,
 	})
 	manager.Start()
 	mnemonic, storeManager, err := manager.NewMnemonicAndEntropyStore("123456")